@@ -0,0 +1,64 @@
+package reform
+
+import (
+	"fmt"
+	"time"
+)
+
+// setAutoTime sets struct's fields with "autocreate" label (if insert is true and they have zero value)
+// or with "autoupdate" label (if insert is false) to the current time.
+// It returns names of columns for all those fields.
+func (q *Querier) setAutoTime(str Struct, insert bool) []string {
+	av, ok := str.View().(AutoTimeView)
+	if !ok {
+		return nil
+	}
+
+	indexes := av.AutoUpdateColumnIndexes()
+	if insert {
+		indexes = av.AutoCreateColumnIndexes()
+	}
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	now := q.now()
+	allColumns := av.Columns()
+	pointers := str.Pointers()
+	columns := make([]string, len(indexes))
+	for i, index := range indexes {
+		columns[i] = allColumns[index]
+
+		switch p := pointers[index].(type) {
+		case *time.Time:
+			if !insert || p.IsZero() {
+				*p = now
+			}
+		case **time.Time:
+			if !insert || *p == nil {
+				t := now
+				*p = &t
+			}
+		default:
+			panic(fmt.Sprintf("reform: unexpected %s field type %T. Please report this bug.", columns[i], p))
+		}
+	}
+	return columns
+}
+
+// withColumns returns columns with added missing extra columns.
+func withColumns(columns []string, extra []string) []string {
+	for _, e := range extra {
+		var found bool
+		for _, c := range columns {
+			if c == e {
+				found = true
+				break
+			}
+		}
+		if !found {
+			columns = append(columns, e)
+		}
+	}
+	return columns
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"reflect"
 )
 
@@ -17,6 +18,13 @@ var (
 
 	// ErrNoPK is returned from various methods when primary key is required and not set.
 	ErrNoPK = errors.New("reform: no primary key")
+
+	// ErrStaleRecord is returned from Querier.Update and Querier.UpdateColumns when record's version
+	// doesn't match version of row in SQL database table, i.e. row was updated concurrently.
+	ErrStaleRecord = errors.New("reform: stale record")
+
+	// ErrNothingToUpdate is returned from Querier.UpdateColumns and Querier.UpdateView when there are no columns to update.
+	ErrNothingToUpdate = errors.New("reform: nothing to update")
 )
 
 // View represents SQL database view or table.
@@ -34,7 +42,7 @@ type View interface {
 	NewStruct() Struct
 }
 
-// Table represents SQL database table with single-column primary key.
+// Table represents SQL database table with single-column or composite primary key.
 // It extends View.
 type Table interface {
 	View
@@ -43,7 +51,75 @@ type Table interface {
 	NewRecord() Record
 
 	// PKColumnIndex returns an index of primary key column for that table in SQL database.
+	// For composite primary key, it returns an index of the first primary key column.
 	PKColumnIndex() uint
+
+	// PKColumnIndexes returns a new slice of indexes of all primary key columns for that table in SQL database.
+	PKColumnIndexes() []uint
+}
+
+// SoftDeleteView is an optional interface for View with soft delete column.
+// It is implemented by generated code for structs with *time.Time field with "softdelete" label in "reform:" tag.
+//
+// Querier's selectors, finders, Count, Update and UpdateColumns ignore rows with non-NULL soft delete column
+// unless Querier is created by WithDeleted. Selectors, finders and Count exclude them with derived table aliased
+// to unqualified view name, so query tails should not use schema-qualified column names.
+// Querier.Delete sets soft delete column instead of deleting a row; Querier.HardDelete deletes it.
+type SoftDeleteView interface {
+	View
+
+	// SoftDeleteColumnIndex returns an index of soft delete column for that view or table in SQL database.
+	SoftDeleteColumnIndex() uint
+}
+
+// AutoTimeView is an optional interface for View with fields automatically set to the current time
+// (see Querier's Clock and TimePrecision).
+// It is implemented by generated code for structs with time.Time or *time.Time fields with
+// "autocreate" or "autoupdate" label in "reform:" tag.
+//
+// Fields with "autocreate" label are set by Querier's insert methods if they have zero value.
+// Fields with "autoupdate" label are set by Querier.Update, Querier.UpdateColumns, and Querier.UpdateView.
+// Querier.InsertColumns and Querier.UpdateColumns always add such columns to the specified ones.
+// In all cases, fields are set before BeforeInserter and BeforeUpdater hooks.
+type AutoTimeView interface {
+	View
+
+	// AutoCreateColumnIndexes returns a new slice of indexes of columns set on insert.
+	AutoCreateColumnIndexes() []uint
+
+	// AutoUpdateColumnIndexes returns a new slice of indexes of columns set on update.
+	AutoUpdateColumnIndexes() []uint
+}
+
+// ForeignKey describes a relationship of a field with "fk=" label in "reform:" tag.
+type ForeignKey struct {
+	Column    string // column of that view or table, e.g. group_id
+	RefTable  string // referenced table, e.g. groups
+	RefColumn string // referenced column, e.g. id
+}
+
+// RelationsView is an optional interface for View with foreign keys used by Querier.Preload.
+// It is implemented by generated code for structs with fields with "fk=table.column" label in "reform:" tag.
+type RelationsView interface {
+	View
+
+	// ForeignKeys returns a new slice of foreign keys for that view or table.
+	ForeignKeys() []ForeignKey
+}
+
+// RelationSetter is an optional interface for Struct used by Querier.Preload to attach related structs.
+type RelationSetter interface {
+	// SetRelation attaches related struct loaded by foreign key column.
+	SetRelation(column string, related Struct)
+}
+
+// VersionedTable is an optional interface for Table with version column used for optimistic locking.
+// It is implemented by generated code for structs with a field with "version" label in "reform:" tag.
+type VersionedTable interface {
+	Table
+
+	// VersionColumnIndex returns an index of version column for that table in SQL database.
+	VersionColumnIndex() uint
 }
 
 // Struct represents a row in SQL database view or table.
@@ -63,7 +139,7 @@ type Struct interface {
 	View() View
 }
 
-// Record represents a row in SQL database table with single-column primary key.
+// Record represents a row in SQL database table with single-column or composite primary key.
 type Record interface {
 	Struct
 
@@ -71,17 +147,31 @@ type Record interface {
 	Table() Table
 
 	// PKValue returns a value of primary key for that record.
+	// For composite primary key, it panics; use PKValues instead.
 	// Returned interface{} value is never untyped nil.
 	PKValue() interface{}
 
 	// PKPointer returns a pointer to primary key field for that record.
+	// For composite primary key, it panics; use PKPointers instead.
 	// Returned interface{} value is never untyped nil.
 	PKPointer() interface{}
 
+	// PKValues returns a slice of primary key field values for that record
+	// in the same order as Table's PKColumnIndexes.
+	// Returned interface{} values are never untyped nils.
+	PKValues() []interface{}
+
+	// PKPointers returns a slice of pointers to primary key fields for that record
+	// in the same order as Table's PKColumnIndexes.
+	// Returned interface{} values are never untyped nils.
+	PKPointers() []interface{}
+
 	// HasPK returns true if record has non-zero primary key set, false otherwise.
+	// For composite primary key, all primary key fields should be non-zero.
 	HasPK() bool
 
 	// SetPK sets record primary key, if possible.
+	// For composite primary key, it panics; use PKPointers instead.
 	//
 	// Deprecated: prefer direct field assignment where possible.
 	SetPK(pk interface{})
@@ -101,6 +191,44 @@ type BeforeUpdater interface {
 	BeforeUpdate() error
 }
 
+// AfterInserter is an optional interface for Record which is used by Querier.Insert, Querier.InsertColumns,
+// and Querier.InsertMulti. It is called after successful insert, when primary key is already filled.
+// It can be used to maintain caches, derived tables, etc.
+// Returning error is returned by those methods, but doesn't undo the insert; use transactions for that.
+type AfterInserter interface {
+	AfterInsert() error
+}
+
+// AfterUpdater is an optional interface for Record which is used by Querier.Update and Querier.UpdateColumns.
+// It is called after successful update.
+// It can be used to maintain caches, derived tables, etc.
+// Returning error is returned by those methods, but doesn't undo the update; use transactions for that.
+type AfterUpdater interface {
+	AfterUpdate() error
+}
+
+// AfterSaver is an optional interface for Record which is used by Querier.Save.
+// It is called after successful update or insert, and after AfterUpdater or AfterInserter.
+// Returning error is returned by Querier.Save, but doesn't undo the save; use transactions for that.
+type AfterSaver interface {
+	AfterSave() error
+}
+
+// BeforeDeleter is an optional interface for Record which is used by Querier.Delete and Querier.HardDelete.
+// It can be used to check if record can be deleted.
+// Returning error aborts operation.
+type BeforeDeleter interface {
+	BeforeDelete() error
+}
+
+// AfterDeleter is an optional interface for Record which is used by Querier.Delete and Querier.HardDelete.
+// It is called after successful delete.
+// It can be used to maintain caches, derived tables, etc.
+// Returning error is returned by those methods, but doesn't undo the delete; use transactions for that.
+type AfterDeleter interface {
+	AfterDelete() error
+}
+
 // AfterFinder is an optional interface for Record which is used by Querier's finders and selectors.
 // It can be used to convert timezones, change data precision, etc.
 // Returning error aborts operation.
@@ -108,6 +236,50 @@ type AfterFinder interface {
 	AfterFind() error
 }
 
+// Context-aware variants of hooks. If Struct or Record implements both variants, only context-aware variant is used.
+// Hook is called with Querier's context and Querier itself, which can be used to make queries
+// in the same transaction (if Querier belongs to TX) with the same tag, logger, etc.
+
+// BeforeInserterContext is a context-aware variant of BeforeInserter.
+type BeforeInserterContext interface {
+	BeforeInsertContext(ctx context.Context, q *Querier) error
+}
+
+// AfterInserterContext is a context-aware variant of AfterInserter.
+type AfterInserterContext interface {
+	AfterInsertContext(ctx context.Context, q *Querier) error
+}
+
+// BeforeUpdaterContext is a context-aware variant of BeforeUpdater.
+type BeforeUpdaterContext interface {
+	BeforeUpdateContext(ctx context.Context, q *Querier) error
+}
+
+// AfterUpdaterContext is a context-aware variant of AfterUpdater.
+type AfterUpdaterContext interface {
+	AfterUpdateContext(ctx context.Context, q *Querier) error
+}
+
+// AfterSaverContext is a context-aware variant of AfterSaver.
+type AfterSaverContext interface {
+	AfterSaveContext(ctx context.Context, q *Querier) error
+}
+
+// BeforeDeleterContext is a context-aware variant of BeforeDeleter.
+type BeforeDeleterContext interface {
+	BeforeDeleteContext(ctx context.Context, q *Querier) error
+}
+
+// AfterDeleterContext is a context-aware variant of AfterDeleter.
+type AfterDeleterContext interface {
+	AfterDeleteContext(ctx context.Context, q *Querier) error
+}
+
+// AfterFinderContext is a context-aware variant of AfterFinder.
+type AfterFinderContext interface {
+	AfterFindContext(ctx context.Context, q *Querier) error
+}
+
 // DBTX is an interface for database connection or transaction.
 // It's implemented by *sql.DB, *sql.Tx, *DB, *TX, and *Querier.
 type DBTX interface {
@@ -158,6 +330,24 @@ const (
 	OutputInserted
 )
 
+// InsertMultiIdMethod is a method of deriving primary keys of rows inserted by a single multi-row INSERT
+// for dialects using LastInsertId method.
+type InsertMultiIdMethod int
+
+const (
+	// NoInsertMultiIds means that primary keys can't be derived from sql.Result.LastInsertId().
+	NoInsertMultiIds InsertMultiIdMethod = iota
+
+	// FirstInsertId means that sql.Result.LastInsertId() returns primary key of the first inserted row,
+	// and primary keys of the next rows are generated with auto_increment_increment step,
+	// if innodb_autoinc_lock_mode is not "interleaved" (MySQL semantics).
+	FirstInsertId
+
+	// LastRowInsertId means that sql.Result.LastInsertId() returns primary key of the last inserted row,
+	// and primary keys of the previous rows are consecutive (SQLite semantics).
+	LastRowInsertId
+)
+
 // SelectLimitMethod is a method of limiting the number of rows in a query result.
 type SelectLimitMethod int
 
@@ -180,6 +370,43 @@ const (
 	EmptyLists
 )
 
+// UpsertMethod is a method of inserting a row or updating existing conflicting row with a single query.
+type UpsertMethod int
+
+const (
+	// OnConflict is a method using "INSERT ... ON CONFLICT (...) DO UPDATE SET ..." SQL syntax.
+	OnConflict UpsertMethod = iota
+
+	// OnDuplicateKeyUpdate is a method using "INSERT ... ON DUPLICATE KEY UPDATE ..." SQL syntax.
+	OnDuplicateKeyUpdate
+
+	// Merge is a method using "MERGE INTO ... USING ..." SQL syntax.
+	Merge
+)
+
+// RowComparisonMethod is a method of comparing several columns with several values in row order.
+type RowComparisonMethod int
+
+const (
+	// RowValues is a method using "(a, b) > (?, ?)" SQL syntax.
+	RowValues RowComparisonMethod = iota
+
+	// ExpandedComparison is a method using "a > ? OR (a = ? AND b > ?)" SQL syntax.
+	ExpandedComparison
+)
+
+// SavepointMethod is a method of creating, rolling back to, and releasing savepoints in transaction.
+type SavepointMethod int
+
+const (
+	// Savepoint is a method using "SAVEPOINT name", "ROLLBACK TO SAVEPOINT name" and "RELEASE SAVEPOINT name" SQL syntax.
+	Savepoint SavepointMethod = iota
+
+	// SaveTransaction is a method using "SAVE TRANSACTION name" and "ROLLBACK TRANSACTION name" SQL syntax.
+	// Savepoints can't be released.
+	SaveTransaction
+)
+
 // Dialect represents differences in various SQL dialects.
 type Dialect interface {
 	// String returns dialect name.
@@ -207,10 +434,143 @@ type Dialect interface {
 	DefaultValuesMethod() DefaultValuesMethod
 }
 
+// UpsertDialect is an optional interface for Dialect supporting Querier.Upsert.
+// It is implemented by all dialects in this repository.
+type UpsertDialect interface {
+	Dialect
+
+	// UpsertMethod returns a method of inserting a row or updating existing conflicting row with a single query.
+	UpsertMethod() UpsertMethod
+}
+
+// upsertMethod returns dialect's UpsertMethod and true, or false if dialect doesn't implement UpsertDialect.
+func upsertMethod(d Dialect) (UpsertMethod, bool) {
+	if ud, ok := d.(UpsertDialect); ok {
+		return ud.UpsertMethod(), true
+	}
+	return 0, false
+}
+
+// InsertMultiIdDialect is an optional interface for Dialect using LastInsertId method
+// that can derive primary keys of rows inserted by Querier.InsertMulti.
+// NoInsertMultiIds is used for dialects without it.
+type InsertMultiIdDialect interface {
+	Dialect
+
+	// InsertMultiIdMethod returns a method of deriving primary keys of rows inserted by a single multi-row INSERT.
+	// It is used only if LastInsertIdMethod returns LastInsertId.
+	InsertMultiIdMethod() InsertMultiIdMethod
+}
+
+// insertMultiIdMethod returns dialect's InsertMultiIdMethod, or NoInsertMultiIds
+// if dialect doesn't implement InsertMultiIdDialect.
+func insertMultiIdMethod(d Dialect) InsertMultiIdMethod {
+	if id, ok := d.(InsertMultiIdDialect); ok {
+		return id.InsertMultiIdMethod()
+	}
+	return NoInsertMultiIds
+}
+
+// MaxParametersDialect is an optional interface for Dialect with known limit of placeholder parameters
+// in a single query, used by Querier.InsertBatch and Querier.Preload.
+// Conservative limit of 999 parameters is used for dialects without it.
+type MaxParametersDialect interface {
+	Dialect
+
+	// MaxParameters returns a maximum number of placeholder parameters in a single query,
+	// or 0 if there is no limit.
+	MaxParameters() int
+}
+
+// maxParameters returns dialect's MaxParameters, or 999 if dialect doesn't implement MaxParametersDialect.
+func maxParameters(d Dialect) int {
+	if md, ok := d.(MaxParametersDialect); ok {
+		return md.MaxParameters()
+	}
+	return 999
+}
+
+// RowComparisonDialect is an optional interface for Dialect that chooses a method of comparing rows
+// for Querier.SelectPage. ExpandedComparison is used for dialects without it.
+type RowComparisonDialect interface {
+	Dialect
+
+	// RowComparisonMethod returns a method of comparing several columns with several values in row order.
+	RowComparisonMethod() RowComparisonMethod
+}
+
+// rowComparisonMethod returns dialect's RowComparisonMethod, or ExpandedComparison
+// if dialect doesn't implement RowComparisonDialect.
+func rowComparisonMethod(d Dialect) RowComparisonMethod {
+	if rd, ok := d.(RowComparisonDialect); ok {
+		return rd.RowComparisonMethod()
+	}
+	return ExpandedComparison
+}
+
+// SavepointDialect is an optional interface for Dialect that chooses savepoints syntax
+// for TX.Savepoint, TX.RollbackTo and TX.Release. Savepoint method is used for dialects without it.
+type SavepointDialect interface {
+	Dialect
+
+	// SavepointMethod returns a method of creating, rolling back to, and releasing savepoints in transaction.
+	SavepointMethod() SavepointMethod
+}
+
+// savepointMethod returns dialect's SavepointMethod, or Savepoint if dialect doesn't implement SavepointDialect.
+func savepointMethod(d Dialect) SavepointMethod {
+	if sd, ok := d.(SavepointDialect); ok {
+		return sd.SavepointMethod()
+	}
+	return Savepoint
+}
+
+// RetryableErrorDialect is an optional interface for Dialect that classifies errors for DB's RetryPolicy.
+// Transactions are not retried for dialects without it.
+type RetryableErrorDialect interface {
+	Dialect
+
+	// RetryableError returns true if given error (typically, serialization failure or deadlock)
+	// means that the whole transaction can be retried. See DB.InTransactionContext.
+	RetryableError(err error) bool
+}
+
+// retryableError returns dialect's RetryableError result, or false if dialect doesn't implement RetryableErrorDialect.
+func retryableError(d Dialect, err error) bool {
+	if rd, ok := d.(RetryableErrorDialect); ok {
+		return rd.RetryableError(err)
+	}
+	return false
+}
+
+// ClassifyErrorDialect is an optional interface for Dialect that classifies driver errors for DBError.
+// Errors are not wrapped for dialects without it.
+type ClassifyErrorDialect interface {
+	Dialect
+
+	// ClassifyError returns a kind of given driver error and constraint name, if available,
+	// or UnclassifiedError. See DBError.
+	ClassifyError(err error) (ErrorKind, string)
+}
+
+// classifyError returns dialect's ClassifyError result, or UnclassifiedError
+// if dialect doesn't implement ClassifyErrorDialect.
+func classifyError(d Dialect, err error) (ErrorKind, string) {
+	if cd, ok := d.(ClassifyErrorDialect); ok {
+		return cd.ClassifyError(err)
+	}
+	return UnclassifiedError, ""
+}
+
 // SetPK sets record's primary key, if possible.
+// It panics for records with composite primary key.
 //
 // Deprecated: prefer direct field assignment where possible.
 func SetPK(r Record, pk interface{}) {
+	if t := r.Table(); len(t.PKColumnIndexes()) > 1 {
+		panic(fmt.Sprintf("reform: %s has composite primary key, use PKPointers", t.Name()))
+	}
+
 	fV := reflect.ValueOf(r.Pointers()[r.Table().PKColumnIndex()]).Elem()
 	pkV := reflect.ValueOf(pk)
 	if t := fV.Type(); t.ConvertibleTo(pkV.Type()) {
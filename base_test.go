@@ -59,6 +59,11 @@ func checkForeignKeys(t testing.TB, q *reform.Querier) {
 	require.True(t, enabled)
 }
 
+// minimalDialect implements only reform.Dialect methods of wrapped dialect, without optional interfaces.
+type minimalDialect struct {
+	reform.Dialect
+}
+
 // withIdentityInsert executes an action with MS SQL IDENTITY_INSERT enabled for a table
 func withIdentityInsert(t testing.TB, q *reform.Querier, table string, action func()) {
 	t.Helper()
@@ -99,6 +104,7 @@ func setupDB(t testing.TB) *reform.DB {
 	pl := reform.NewPrintfLogger(t.Logf)
 	pl.LogTypes = true
 	db.Logger = pl
+	db.TimePrecision = time.Second // the lowest precision of all supported databases
 	db.Querier = db.WithTag("test:%s", t.Name())
 
 	checkForeignKeys(t, db.Querier)
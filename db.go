@@ -24,10 +24,39 @@ type DBInterface interface {
 // check interface
 var _ DBInterface = (*sql.DB)(nil)
 
+// RetryPolicy describes how DB.InTransactionContext retries transactions failed with retryable errors
+// (see Dialect's RetryableError).
+type RetryPolicy struct {
+	// MaxAttempts is a maximum number of attempts, including the first one.
+	// Values less than 2 disable retries.
+	MaxAttempts int
+
+	// Backoff returns a delay before given retry (starting from 1). If nil, there is no delay.
+	Backoff func(retry int) time.Duration
+}
+
+// ExponentialBackoff returns RetryPolicy's Backoff function with delays starting from base
+// and doubling for each retry, up to max.
+func ExponentialBackoff(base, max time.Duration) func(retry int) time.Duration {
+	return func(retry int) time.Duration {
+		d := base
+		for i := 1; i < retry && d < max; i++ {
+			d *= 2
+		}
+		if d > max {
+			d = max
+		}
+		return d
+	}
+}
+
 // DB represents a connection to SQL database.
 type DB struct {
 	*Querier
 	db DBInterface
+
+	// RetryPolicy is used by InTransaction and InTransactionContext. If nil, transactions are not retried.
+	RetryPolicy *RetryPolicy
 }
 
 // NewDB creates new DB object for given SQL database connection.
@@ -65,7 +94,15 @@ func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*TX, error) {
 	if err != nil {
 		return nil, err
 	}
-	return newTX(ctx, tx, db.Dialect, db.Logger), nil
+	t := newTX(ctx, tx, db.Dialect, db.Logger)
+	t.Clock = db.Clock
+	t.TimePrecision = db.TimePrecision
+	t.idStep = db.idStep
+	if db.StmtCache != nil {
+		t.StmtCache = db.StmtCache
+		t.txStmts = &txStmts{tx: tx, m: make(map[string]*sql.Stmt)}
+	}
+	return t, nil
 }
 
 // InTransaction wraps function execution in transaction with Querier's context and default options,
@@ -76,7 +113,34 @@ func (db *DB) InTransaction(f func(t *TX) error) error {
 
 // InTransactionContext wraps function execution in transaction with given context and options (can be nil),
 // rolling back it in case of error or panic, committing otherwise.
+//
+// If DB's RetryPolicy is set, and f or Commit returns an error classified as retryable by Dialect's RetryableError
+// (see RetryableErrorDialect), f is called again in a new transaction, so it should not have side effects outside of it.
+// Retries stop when context is canceled; context's error is returned in that case.
 func (db *DB) InTransactionContext(ctx context.Context, opts *sql.TxOptions, f func(t *TX) error) error {
+	for attempt := 1; ; attempt++ {
+		err := db.inTransaction(ctx, opts, f)
+		p := db.RetryPolicy
+		if err == nil || p == nil || attempt >= p.MaxAttempts || !retryableError(db.Dialect, err) {
+			return err
+		}
+
+		var delay time.Duration
+		if p.Backoff != nil {
+			delay = p.Backoff(attempt)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// inTransaction makes a single attempt of InTransactionContext.
+func (db *DB) inTransaction(ctx context.Context, opts *sql.TxOptions, f func(t *TX) error) error {
 	tx, err := db.BeginTx(ctx, opts)
 	if err != nil {
 		return err
@@ -100,6 +164,21 @@ func (db *DB) InTransactionContext(ctx context.Context, opts *sql.TxOptions, f f
 	return err
 }
 
+// InsertBatchInTransaction calls InsertBatch in transaction with Querier's context and default options,
+// so either all structs are inserted, or none of them.
+func (db *DB) InsertBatchInTransaction(structs ...Struct) (uint, error) {
+	var inserted uint
+	err := db.InTransaction(func(t *TX) error {
+		var e error
+		inserted, e = t.InsertBatch(structs...)
+		return e
+	})
+	if err != nil {
+		return 0, err
+	}
+	return inserted, nil
+}
+
 // check interfaces
 var (
 	_ DBTX        = (*DB)(nil)
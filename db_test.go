@@ -1,20 +1,30 @@
 package reform_test
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/AlekSi/pointer"
 	"github.com/brianvoe/gofakeit/v6"
+	mssqlDriver "github.com/denisenkom/go-mssqldb"
+	mysqlDriver "github.com/go-sql-driver/mysql"
 	"github.com/jackc/pgx"
 	"github.com/jackc/pgx/stdlib"
 	"github.com/lib/pq"
+	sqlite3Driver "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"gopkg.in/reform.v1"
+	"gopkg.in/reform.v1/dialects/mssql" //nolint:staticcheck
+	"gopkg.in/reform.v1/dialects/mysql"
 	"gopkg.in/reform.v1/dialects/postgresql"
+	"gopkg.in/reform.v1/dialects/sqlite3"
+	"gopkg.in/reform.v1/dialects/sqlserver"
 	. "gopkg.in/reform.v1/internal/test/models"
 )
 
@@ -171,3 +181,382 @@ func TestInTransaction(t *testing.T) {
 	assert.NoError(t, db.Reload(person))
 	assert.NoError(t, db.Delete(person))
 }
+
+func TestInsertBatchInTransaction(t *testing.T) {
+	db := setupDB(t)
+	defer teardown(t, db)
+
+	before, err := db.Count(PersonTable, "")
+	require.NoError(t, err)
+
+	// the first chunk is valid, the last one mixes structs with and without primary keys
+	n := db.Dialect.(reform.MaxParametersDialect).MaxParameters()/len(PersonTable.Columns()) + 1
+	structs := make([]reform.Struct, n)
+	for i := range structs {
+		structs[i] = &Person{Name: gofakeit.Name()}
+	}
+	structs = append(structs, &Person{ID: 1, Name: gofakeit.Name()})
+
+	inserted, err := db.InsertBatchInTransaction(structs...)
+	assert.Error(t, err)
+	assert.Equal(t, uint(0), inserted)
+
+	after, err := db.Count(PersonTable, "")
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+}
+
+func TestSavepoints(t *testing.T) {
+	db, tx := setupTX(t)
+	defer teardown(t, db)
+	defer func() {
+		assert.NoError(t, tx.Rollback())
+	}()
+
+	count := func() int {
+		t.Helper()
+		n, err := tx.Count(DocumentTable, "")
+		require.NoError(t, err)
+		return n
+	}
+	before := count()
+
+	require.NoError(t, tx.Insert(&Document{Title: "First"}))
+	require.NoError(t, tx.Savepoint("sp"))
+	require.NoError(t, tx.Insert(&Document{Title: "Second"}))
+	assert.Equal(t, before+2, count())
+	require.NoError(t, tx.RollbackTo("sp"))
+	assert.Equal(t, before+1, count())
+	require.NoError(t, tx.Insert(&Document{Title: "Third"}))
+	require.NoError(t, tx.Release("sp"))
+	assert.Equal(t, before+2, count())
+
+	// nested transactions
+	errRollback := errors.New("rollback")
+	err := tx.InTransaction(func(t1 *reform.TX) error {
+		require.NoError(t, t1.Insert(&Document{Title: "Fourth"}))
+
+		err := t1.InTransaction(func(t2 *reform.TX) error {
+			require.NoError(t, t2.Insert(&Document{Title: "Fifth"}))
+			return errRollback
+		})
+		assert.Equal(t, errRollback, err)
+
+		return t1.InTransaction(func(t2 *reform.TX) error {
+			return t2.Insert(&Document{Title: "Sixth"})
+		})
+	})
+	require.NoError(t, err)
+	assert.Equal(t, before+4, count())
+
+	assert.Panics(t, func() {
+		_ = tx.InTransaction(func(t1 *reform.TX) error {
+			require.NoError(t, t1.Insert(&Document{Title: "Seventh"}))
+			panic("rollback")
+		})
+	})
+	assert.Equal(t, before+4, count())
+
+	n, err := tx.Count(DocumentTable, "WHERE title IN ("+tx.Placeholder(1)+", "+tx.Placeholder(2)+")", "Second", "Fifth")
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	// Savepoint method is used for dialects without SavepointDialect
+	if tx.Dialect.(reform.SavepointDialect).SavepointMethod() == reform.Savepoint {
+		minimal := *tx
+		minimal.Querier = tx.WithTag("")
+		minimal.Dialect = minimalDialect{tx.Dialect}
+		err = minimal.InTransaction(func(t1 *reform.TX) error {
+			return t1.Insert(&Document{Title: "Eighth"})
+		})
+		require.NoError(t, err)
+		assert.Equal(t, before+5, count())
+	}
+}
+
+// retryableErrors contains examples of retryable driver errors for each dialect.
+var retryableErrors = map[reform.Dialect][]error{
+	postgresql.Dialect: {&pq.Error{Code: "40001"}, pgx.PgError{Code: "40P01"}},
+	mysql.Dialect:      {&mysqlDriver.MySQLError{Number: 1213}, &mysqlDriver.MySQLError{Number: 1205}},
+	sqlite3.Dialect:    {sqlite3Driver.Error{Code: sqlite3Driver.ErrBusy}},
+	mssql.Dialect:      {mssqlDriver.Error{Number: 1205}}, //nolint:staticcheck
+	sqlserver.Dialect:  {mssqlDriver.Error{Number: 1205}, mssqlDriver.Error{Number: 3960}},
+}
+
+func TestRetryableError(t *testing.T) {
+	t.Parallel()
+
+	for d, errs := range retryableErrors {
+		dialect := d.(reform.RetryableErrorDialect)
+		for _, err := range errs {
+			assert.True(t, dialect.RetryableError(err), "%s: %#v", dialect, err)
+			assert.True(t, dialect.RetryableError(fmt.Errorf("wrapped: %w", err)), "%s: %#v", dialect, err)
+		}
+
+		for _, err := range []error{
+			errors.New("40001"),
+			&pq.Error{Code: "23505"},
+			&mysqlDriver.MySQLError{Number: 1062},
+			sqlite3Driver.Error{Code: sqlite3Driver.ErrConstraint},
+			mssqlDriver.Error{Number: 2627},
+			nil,
+		} {
+			assert.False(t, dialect.RetryableError(err), "%s: %#v", dialect, err)
+		}
+	}
+}
+
+func TestInTransactionRetry(t *testing.T) {
+	db := setupDB(t)
+	defer teardown(t, db)
+
+	retryable := fmt.Errorf("wrapped: %w", retryableErrors[db.Dialect][0])
+	db.RetryPolicy = &reform.RetryPolicy{
+		MaxAttempts: 3,
+		Backoff:     reform.ExponentialBackoff(time.Millisecond, 5*time.Millisecond),
+	}
+
+	count := func() int {
+		t.Helper()
+		n, err := db.Count(DocumentTable, "WHERE title = "+db.Placeholder(1), "Retry")
+		require.NoError(t, err)
+		return n
+	}
+
+	// succeeds on the last attempt
+	var attempts int
+	err := db.InTransaction(func(tx *reform.TX) error {
+		attempts++
+		require.NoError(t, tx.Insert(&Document{Title: "Retry"}))
+		if attempts < 3 {
+			return retryable
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, 1, count())
+	_, err = db.DeleteFrom(DocumentTable, "WHERE title = "+db.Placeholder(1), "Retry")
+	require.NoError(t, err)
+
+	// too many attempts
+	attempts = 0
+	err = db.InTransaction(func(tx *reform.TX) error {
+		attempts++
+		return retryable
+	})
+	assert.Equal(t, retryable, err)
+	assert.Equal(t, 3, attempts)
+
+	// not retryable error
+	attempts = 0
+	errNotRetryable := errors.New("not retryable")
+	err = db.InTransaction(func(tx *reform.TX) error {
+		attempts++
+		return errNotRetryable
+	})
+	assert.Equal(t, errNotRetryable, err)
+	assert.Equal(t, 1, attempts)
+
+	// dialect without RetryableErrorDialect
+	attempts = 0
+	minimal := reform.NewDBFromInterface(db.DBInterface(), minimalDialect{db.Dialect}, db.Logger)
+	minimal.RetryPolicy = db.RetryPolicy
+	err = minimal.InTransaction(func(tx *reform.TX) error {
+		attempts++
+		return retryable
+	})
+	assert.Equal(t, retryable, err)
+	assert.Equal(t, 1, attempts)
+
+	// canceled context
+	attempts = 0
+	ctx, cancel := context.WithCancel(context.Background())
+	db.RetryPolicy.Backoff = func(int) time.Duration { return time.Hour }
+	err = db.InTransactionContext(ctx, nil, func(tx *reform.TX) error {
+		attempts++
+		cancel()
+		return retryable
+	})
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 1, attempts)
+
+	assert.Equal(t, []time.Duration{time.Millisecond, 2 * time.Millisecond, 4 * time.Millisecond, 5 * time.Millisecond},
+		[]time.Duration{
+			reform.ExponentialBackoff(time.Millisecond, 5*time.Millisecond)(1),
+			reform.ExponentialBackoff(time.Millisecond, 5*time.Millisecond)(2),
+			reform.ExponentialBackoff(time.Millisecond, 5*time.Millisecond)(3),
+			reform.ExponentialBackoff(time.Millisecond, 5*time.Millisecond)(4),
+		})
+	assert.Equal(t, 0, count())
+}
+
+// countingDB is a DBInterface that counts queries and transactions.
+type countingDB struct {
+	reform.DBInterface
+	queries int
+	begins  int
+}
+
+func (c *countingDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	c.queries++
+	return c.DBInterface.ExecContext(ctx, query, args...)
+}
+
+func (c *countingDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	c.queries++
+	return c.DBInterface.QueryContext(ctx, query, args...)
+}
+
+func (c *countingDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	c.queries++
+	return c.DBInterface.QueryRowContext(ctx, query, args...)
+}
+
+func (c *countingDB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	c.begins++
+	return c.DBInterface.BeginTx(ctx, opts)
+}
+
+func TestReplicas(t *testing.T) {
+	db := setupDB(t)
+	defer teardown(t, db)
+
+	// all of them use the same connection pool
+	primary := &countingDB{DBInterface: db.DBInterface()}
+	replica1 := &countingDB{DBInterface: db.DBInterface()}
+	replica2 := &countingDB{DBInterface: db.DBInterface()}
+	rdb := reform.NewDBWithReplicas(primary, []reform.DBInterface{replica1, replica2}, reform.RoundRobin, db.Dialect, db.Logger)
+
+	var _ reform.DBTX = rdb
+	var _ reform.DBTXContext = rdb
+
+	_, err := rdb.SelectAllFrom(PersonTable, "")
+	require.NoError(t, err)
+	_, err = rdb.FindByPrimaryKeyFrom(PersonTable, 102)
+	require.NoError(t, err)
+	_, err = rdb.WithTag("replica").Count(PersonTable, "")
+	require.NoError(t, err)
+	assert.Equal(t, 0, primary.queries)
+	assert.Equal(t, 2, replica1.queries)
+	assert.Equal(t, 1, replica2.queries)
+
+	_, err = rdb.WithPrimary().Count(PersonTable, "")
+	require.NoError(t, err)
+	_, err = rdb.Exec(fmt.Sprintf("UPDATE %s SET name = name WHERE id = 0", rdb.QualifiedView(PersonTable)))
+	require.NoError(t, err)
+	assert.Equal(t, 2, primary.queries)
+	assert.Equal(t, 3, replica1.queries+replica2.queries)
+
+	err = rdb.InTransaction(func(tx *reform.TX) error {
+		_, e := tx.Count(PersonTable, "")
+		return e
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, primary.begins)
+	assert.Equal(t, 0, replica1.begins+replica2.begins)
+	assert.Equal(t, 3, replica1.queries+replica2.queries)
+
+	// stale record is checked on primary
+	doc := &Document{Title: "Replicas"}
+	require.NoError(t, rdb.Insert(doc))
+	defer func() {
+		assert.NoError(t, rdb.HardDelete(doc))
+	}()
+	stale := *doc
+	require.NoError(t, rdb.Update(doc))
+	assert.Equal(t, reform.ErrStaleRecord, rdb.Update(&stale))
+	assert.Equal(t, 3, replica1.queries+replica2.queries)
+
+	rdb = reform.NewDBWithReplicas(primary, []reform.DBInterface{replica1, replica2}, reform.LeastLoaded, db.Dialect, db.Logger)
+	_, err = rdb.SelectAllFrom(PersonTable, "")
+	require.NoError(t, err)
+	assert.Equal(t, 3, replica1.queries, "replicas without Stats() should be considered idle")
+}
+
+func TestReplicasLeastLoaded(t *testing.T) {
+	db := setupDB(t)
+	defer teardown(t, db)
+	replica1, replica2 := setupDB(t), setupDB(t)
+	defer teardown(t, replica1)
+	defer teardown(t, replica2)
+
+	// each pool has a single connection
+	sqlDB1, sqlDB2 := replica1.DBInterface().(*sql.DB), replica2.DBInterface().(*sql.DB)
+	rdb := reform.NewDBWithReplicas(db.DBInterface(), []reform.DBInterface{sqlDB1, sqlDB2}, reform.LeastLoaded, db.Dialect, db.Logger)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	q := rdb.WithContext(ctx)
+
+	for _, busy := range []*sql.DB{sqlDB1, sqlDB2} {
+		conn, err := busy.Conn(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1, busy.Stats().InUse)
+
+		rows, err := q.SelectRows(PersonTable, "")
+		require.NoError(t, err, "query should not wait for busy replica's connection")
+		assert.Equal(t, 1, sqlDB1.Stats().InUse)
+		assert.Equal(t, 1, sqlDB2.Stats().InUse)
+		require.NoError(t, rows.Close())
+
+		require.NoError(t, conn.Close())
+		assert.Equal(t, 0, busy.Stats().InUse)
+	}
+}
+
+func TestStmtCache(t *testing.T) {
+	db := setupDB(t)
+	defer teardown(t, db)
+
+	db.StmtCache = reform.NewStmtCache(2)
+	defer func() {
+		assert.NoError(t, db.StmtCache.Close())
+		assert.Zero(t, db.StmtCache.Len())
+	}()
+
+	for i := 0; i < 3; i++ {
+		count, err := db.Count(PersonTable, "")
+		require.NoError(t, err)
+		assert.NotZero(t, count)
+	}
+	assert.Equal(t, 1, db.StmtCache.Len())
+
+	person, err := db.FindByPrimaryKeyFrom(PersonTable, 102)
+	require.NoError(t, err)
+	assert.Equal(t, "Elfrieda Abbott", person.(*Person).Name)
+	_, err = db.FindByPrimaryKeyFrom(PersonTable, 103)
+	require.NoError(t, err)
+	assert.Equal(t, 2, db.StmtCache.Len())
+
+	// least recently used Count statement is evicted
+	_, err = db.SelectAllFrom(ProjectTable, "")
+	require.NoError(t, err)
+	assert.Equal(t, 2, db.StmtCache.Len())
+
+	err = db.InTransaction(func(tx *reform.TX) error {
+		assert.Equal(t, db.StmtCache, tx.StmtCache)
+		for i := 0; i < 3; i++ {
+			if _, e := tx.Count(PersonTable, ""); e != nil {
+				return e
+			}
+		}
+		person, e := tx.FindByPrimaryKeyFrom(PersonTable, 102)
+		if e != nil {
+			return e
+		}
+		assert.Equal(t, "Elfrieda Abbott", person.(*Person).Name)
+
+		// query that is not cached is sent as is, and not added to cache
+		_, e = tx.SelectAllFrom(PersonProjectView, "")
+		return e
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, db.StmtCache.Len())
+
+	// statements used after transaction ends are not closed
+	_, err = db.FindByPrimaryKeyFrom(PersonTable, 102)
+	require.NoError(t, err)
+	_, err = db.Count(PersonTable, "")
+	require.NoError(t, err)
+}
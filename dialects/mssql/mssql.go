@@ -4,7 +4,11 @@
 package mssql // import "gopkg.in/reform.v1/dialects/mssql"
 
 import (
+	"regexp"
+	"strings"
+
 	"gopkg.in/reform.v1"
+	"gopkg.in/reform.v1/internal"
 )
 
 type mssql struct{}
@@ -33,6 +37,10 @@ func (mssql) LastInsertIdMethod() reform.LastInsertIdMethod {
 	return reform.OutputInserted
 }
 
+func (mssql) InsertMultiIdMethod() reform.InsertMultiIdMethod {
+	return reform.NoInsertMultiIds
+}
+
 func (mssql) SelectLimitMethod() reform.SelectLimitMethod {
 	return reform.SelectTop
 }
@@ -41,10 +49,71 @@ func (mssql) DefaultValuesMethod() reform.DefaultValuesMethod {
 	return reform.DefaultValues
 }
 
+func (mssql) UpsertMethod() reform.UpsertMethod {
+	return reform.Merge
+}
+
+func (mssql) MaxParameters() int {
+	return 2100
+}
+
+func (mssql) RowComparisonMethod() reform.RowComparisonMethod {
+	return reform.ExpandedComparison
+}
+
+func (mssql) SavepointMethod() reform.SavepointMethod {
+	return reform.SaveTransaction
+}
+
+func (mssql) RetryableError(err error) bool {
+	n, _ := internal.ErrorNumber(err)
+	switch n {
+	case 1205, 3960: // deadlock victim, snapshot isolation update conflict
+		return true
+	default:
+		return false
+	}
+}
+
+var (
+	uniqueRE     = regexp.MustCompile(`(?:constraint|unique index) '([^']+)'`)
+	constraintRE = regexp.MustCompile(`constraint "([^"]+)"`)
+)
+
+func (mssql) ClassifyError(err error) (reform.ErrorKind, string) {
+	n, _ := internal.ErrorNumber(err)
+	switch n {
+	case 2627, 2601: // unique constraint or primary key, unique index
+		return reform.UniqueViolation, internal.MatchMessage(err, uniqueRE)
+	case 547: // foreign key or check constraint
+		if strings.Contains(err.Error(), "CHECK constraint") {
+			return reform.CheckViolation, internal.MatchMessage(err, constraintRE)
+		}
+		return reform.ForeignKeyViolation, internal.MatchMessage(err, constraintRE)
+	case 515: // cannot insert NULL
+		return reform.NotNullViolation, ""
+	case 1205: // deadlock victim
+		return reform.Deadlock, ""
+	case 1222: // lock request timeout
+		return reform.Timeout, ""
+	default:
+		return reform.UnclassifiedError, ""
+	}
+}
+
 // Dialect implements reform.Dialect for Microsoft SQL Server.
 //
 // Deprecated: Use sqlserver.Dialect instead. https://github.com/denisenkom/go-mssqldb#deprecated
 var Dialect mssql
 
-// check interface
-var _ reform.Dialect = Dialect
+// check interfaces
+var (
+	_ reform.Dialect               = Dialect
+	_ reform.UpsertDialect         = Dialect
+	_ reform.InsertMultiIdDialect  = Dialect
+	_ reform.MaxParametersDialect  = Dialect
+	_ reform.RowComparisonDialect  = Dialect
+	_ reform.SavepointDialect      = Dialect
+	_ reform.RetryableErrorDialect = Dialect
+	_ reform.ClassifyErrorDialect  = Dialect
+)
@@ -2,7 +2,10 @@
 package mysql // import "gopkg.in/reform.v1/dialects/mysql"
 
 import (
+	"regexp"
+
 	"gopkg.in/reform.v1"
+	"gopkg.in/reform.v1/internal"
 )
 
 type mysql struct{}
@@ -31,6 +34,10 @@ func (mysql) LastInsertIdMethod() reform.LastInsertIdMethod {
 	return reform.LastInsertId
 }
 
+func (mysql) InsertMultiIdMethod() reform.InsertMultiIdMethod {
+	return reform.FirstInsertId
+}
+
 func (mysql) SelectLimitMethod() reform.SelectLimitMethod {
 	return reform.Limit
 }
@@ -39,8 +46,69 @@ func (mysql) DefaultValuesMethod() reform.DefaultValuesMethod {
 	return reform.EmptyLists
 }
 
+func (mysql) UpsertMethod() reform.UpsertMethod {
+	return reform.OnDuplicateKeyUpdate
+}
+
+func (mysql) MaxParameters() int {
+	return 65535
+}
+
+func (mysql) RowComparisonMethod() reform.RowComparisonMethod {
+	return reform.RowValues
+}
+
+func (mysql) SavepointMethod() reform.SavepointMethod {
+	return reform.Savepoint
+}
+
+func (mysql) RetryableError(err error) bool {
+	n, _ := internal.ErrorNumber(err)
+	switch n {
+	case 1205, 1213: // ER_LOCK_WAIT_TIMEOUT, ER_LOCK_DEADLOCK
+		return true
+	default:
+		return false
+	}
+}
+
+var (
+	uniqueRE     = regexp.MustCompile(`for key '([^']+)'`)
+	foreignKeyRE = regexp.MustCompile("CONSTRAINT `([^`]+)`")
+	checkRE      = regexp.MustCompile(`constraint '([^']+)'`)
+)
+
+func (mysql) ClassifyError(err error) (reform.ErrorKind, string) {
+	n, _ := internal.ErrorNumber(err)
+	switch n {
+	case 1062: // ER_DUP_ENTRY
+		return reform.UniqueViolation, internal.MatchMessage(err, uniqueRE)
+	case 1216, 1217, 1451, 1452: // ER_NO_REFERENCED_ROW, ER_ROW_IS_REFERENCED, and their _2 variants
+		return reform.ForeignKeyViolation, internal.MatchMessage(err, foreignKeyRE)
+	case 1048, 1364: // ER_BAD_NULL_ERROR, ER_NO_DEFAULT_FOR_FIELD
+		return reform.NotNullViolation, ""
+	case 3819: // ER_CHECK_CONSTRAINT_VIOLATED
+		return reform.CheckViolation, internal.MatchMessage(err, checkRE)
+	case 1213: // ER_LOCK_DEADLOCK
+		return reform.Deadlock, ""
+	case 1205, 3024: // ER_LOCK_WAIT_TIMEOUT, ER_QUERY_TIMEOUT
+		return reform.Timeout, ""
+	default:
+		return reform.UnclassifiedError, ""
+	}
+}
+
 // Dialect implements reform.Dialect for MySQL.
 var Dialect mysql
 
-// check interface
-var _ reform.Dialect = Dialect
+// check interfaces
+var (
+	_ reform.Dialect               = Dialect
+	_ reform.UpsertDialect         = Dialect
+	_ reform.InsertMultiIdDialect  = Dialect
+	_ reform.MaxParametersDialect  = Dialect
+	_ reform.RowComparisonDialect  = Dialect
+	_ reform.SavepointDialect      = Dialect
+	_ reform.RetryableErrorDialect = Dialect
+	_ reform.ClassifyErrorDialect  = Dialect
+)
@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"gopkg.in/reform.v1"
+	"gopkg.in/reform.v1/internal"
 )
 
 type postgresql struct{}
@@ -33,6 +34,10 @@ func (postgresql) LastInsertIdMethod() reform.LastInsertIdMethod {
 	return reform.Returning
 }
 
+func (postgresql) InsertMultiIdMethod() reform.InsertMultiIdMethod {
+	return reform.NoInsertMultiIds
+}
+
 func (postgresql) SelectLimitMethod() reform.SelectLimitMethod {
 	return reform.Limit
 }
@@ -41,8 +46,63 @@ func (postgresql) DefaultValuesMethod() reform.DefaultValuesMethod {
 	return reform.DefaultValues
 }
 
+func (postgresql) UpsertMethod() reform.UpsertMethod {
+	return reform.OnConflict
+}
+
+func (postgresql) MaxParameters() int {
+	return 65535
+}
+
+func (postgresql) RowComparisonMethod() reform.RowComparisonMethod {
+	return reform.RowValues
+}
+
+func (postgresql) SavepointMethod() reform.SavepointMethod {
+	return reform.Savepoint
+}
+
+func (postgresql) RetryableError(err error) bool {
+	switch internal.SQLState(err) {
+	case "40001", "40P01": // serialization_failure, deadlock_detected
+		return true
+	default:
+		return false
+	}
+}
+
+func (postgresql) ClassifyError(err error) (reform.ErrorKind, string) {
+	var kind reform.ErrorKind
+	switch internal.SQLState(err) {
+	case "23505": // unique_violation
+		kind = reform.UniqueViolation
+	case "23503": // foreign_key_violation
+		kind = reform.ForeignKeyViolation
+	case "23502": // not_null_violation
+		kind = reform.NotNullViolation
+	case "23514": // check_violation
+		kind = reform.CheckViolation
+	case "40P01": // deadlock_detected
+		kind = reform.Deadlock
+	case "57014", "55P03": // query_canceled (statement_timeout), lock_not_available (lock_timeout)
+		kind = reform.Timeout
+	default:
+		return reform.UnclassifiedError, ""
+	}
+	return kind, internal.ConstraintName(err)
+}
+
 // Dialect implements reform.Dialect for PostgreSQL.
 var Dialect postgresql
 
-// check interface
-var _ reform.Dialect = Dialect
+// check interfaces
+var (
+	_ reform.Dialect               = Dialect
+	_ reform.UpsertDialect         = Dialect
+	_ reform.InsertMultiIdDialect  = Dialect
+	_ reform.MaxParametersDialect  = Dialect
+	_ reform.RowComparisonDialect  = Dialect
+	_ reform.SavepointDialect      = Dialect
+	_ reform.RetryableErrorDialect = Dialect
+	_ reform.ClassifyErrorDialect  = Dialect
+)
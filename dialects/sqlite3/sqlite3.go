@@ -2,7 +2,10 @@
 package sqlite3 // import "gopkg.in/reform.v1/dialects/sqlite3"
 
 import (
+	"regexp"
+
 	"gopkg.in/reform.v1"
+	"gopkg.in/reform.v1/internal"
 )
 
 type sqlite3 struct{}
@@ -31,6 +34,10 @@ func (sqlite3) LastInsertIdMethod() reform.LastInsertIdMethod {
 	return reform.LastInsertId
 }
 
+func (sqlite3) InsertMultiIdMethod() reform.InsertMultiIdMethod {
+	return reform.LastRowInsertId
+}
+
 func (sqlite3) SelectLimitMethod() reform.SelectLimitMethod {
 	return reform.Limit
 }
@@ -39,8 +46,65 @@ func (sqlite3) DefaultValuesMethod() reform.DefaultValuesMethod {
 	return reform.DefaultValues
 }
 
+func (sqlite3) UpsertMethod() reform.UpsertMethod {
+	return reform.OnConflict
+}
+
+func (sqlite3) MaxParameters() int {
+	// SQLITE_MAX_VARIABLE_NUMBER default for SQLite versions before 3.32.0
+	return 999
+}
+
+func (sqlite3) RowComparisonMethod() reform.RowComparisonMethod {
+	return reform.RowValues
+}
+
+func (sqlite3) SavepointMethod() reform.SavepointMethod {
+	return reform.Savepoint
+}
+
+func (sqlite3) RetryableError(err error) bool {
+	n, _ := internal.ErrorNumber(err)
+	switch n {
+	case 5, 6: // SQLITE_BUSY, SQLITE_LOCKED
+		return true
+	default:
+		return false
+	}
+}
+
+var checkRE = regexp.MustCompile(`CHECK constraint failed: (\w+)`)
+
+func (sqlite3) ClassifyError(err error) (reform.ErrorKind, string) {
+	n, _ := internal.ExtendedErrorNumber(err)
+	switch n {
+	case 2067, 1555: // SQLITE_CONSTRAINT_UNIQUE, SQLITE_CONSTRAINT_PRIMARYKEY
+		return reform.UniqueViolation, ""
+	case 787: // SQLITE_CONSTRAINT_FOREIGNKEY
+		return reform.ForeignKeyViolation, ""
+	case 1299: // SQLITE_CONSTRAINT_NOTNULL
+		return reform.NotNullViolation, ""
+	case 275: // SQLITE_CONSTRAINT_CHECK
+		return reform.CheckViolation, internal.MatchMessage(err, checkRE)
+	}
+
+	if n, _ = internal.ErrorNumber(err); n == 5 { // SQLITE_BUSY
+		return reform.Timeout, ""
+	}
+	return reform.UnclassifiedError, ""
+}
+
 // Dialect implements reform.Dialect for SQLite3.
 var Dialect sqlite3
 
-// check interface
-var _ reform.Dialect = Dialect
+// check interfaces
+var (
+	_ reform.Dialect               = Dialect
+	_ reform.UpsertDialect         = Dialect
+	_ reform.InsertMultiIdDialect  = Dialect
+	_ reform.MaxParametersDialect  = Dialect
+	_ reform.RowComparisonDialect  = Dialect
+	_ reform.SavepointDialect      = Dialect
+	_ reform.RetryableErrorDialect = Dialect
+	_ reform.ClassifyErrorDialect  = Dialect
+)
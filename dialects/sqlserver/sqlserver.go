@@ -2,9 +2,12 @@
 package sqlserver // import "gopkg.in/reform.v1/dialects/sqlserver"
 
 import (
+	"regexp"
 	"strconv"
+	"strings"
 
 	"gopkg.in/reform.v1"
+	"gopkg.in/reform.v1/internal"
 )
 
 type sqlserver struct{}
@@ -33,6 +36,10 @@ func (sqlserver) LastInsertIdMethod() reform.LastInsertIdMethod {
 	return reform.OutputInserted
 }
 
+func (sqlserver) InsertMultiIdMethod() reform.InsertMultiIdMethod {
+	return reform.NoInsertMultiIds
+}
+
 func (sqlserver) SelectLimitMethod() reform.SelectLimitMethod {
 	return reform.SelectTop
 }
@@ -41,8 +48,69 @@ func (sqlserver) DefaultValuesMethod() reform.DefaultValuesMethod {
 	return reform.DefaultValues
 }
 
+func (sqlserver) UpsertMethod() reform.UpsertMethod {
+	return reform.Merge
+}
+
+func (sqlserver) MaxParameters() int {
+	return 2100
+}
+
+func (sqlserver) RowComparisonMethod() reform.RowComparisonMethod {
+	return reform.ExpandedComparison
+}
+
+func (sqlserver) SavepointMethod() reform.SavepointMethod {
+	return reform.SaveTransaction
+}
+
+func (sqlserver) RetryableError(err error) bool {
+	n, _ := internal.ErrorNumber(err)
+	switch n {
+	case 1205, 3960: // deadlock victim, snapshot isolation update conflict
+		return true
+	default:
+		return false
+	}
+}
+
+var (
+	uniqueRE     = regexp.MustCompile(`(?:constraint|unique index) '([^']+)'`)
+	constraintRE = regexp.MustCompile(`constraint "([^"]+)"`)
+)
+
+func (sqlserver) ClassifyError(err error) (reform.ErrorKind, string) {
+	n, _ := internal.ErrorNumber(err)
+	switch n {
+	case 2627, 2601: // unique constraint or primary key, unique index
+		return reform.UniqueViolation, internal.MatchMessage(err, uniqueRE)
+	case 547: // foreign key or check constraint
+		if strings.Contains(err.Error(), "CHECK constraint") {
+			return reform.CheckViolation, internal.MatchMessage(err, constraintRE)
+		}
+		return reform.ForeignKeyViolation, internal.MatchMessage(err, constraintRE)
+	case 515: // cannot insert NULL
+		return reform.NotNullViolation, ""
+	case 1205: // deadlock victim
+		return reform.Deadlock, ""
+	case 1222: // lock request timeout
+		return reform.Timeout, ""
+	default:
+		return reform.UnclassifiedError, ""
+	}
+}
+
 // Dialect implements reform.Dialect for Microsoft SQL Server.
 var Dialect sqlserver
 
-// check interface
-var _ reform.Dialect = Dialect
+// check interfaces
+var (
+	_ reform.Dialect               = Dialect
+	_ reform.UpsertDialect         = Dialect
+	_ reform.InsertMultiIdDialect  = Dialect
+	_ reform.MaxParametersDialect  = Dialect
+	_ reform.RowComparisonDialect  = Dialect
+	_ reform.SavepointDialect      = Dialect
+	_ reform.RetryableErrorDialect = Dialect
+	_ reform.ClassifyErrorDialect  = Dialect
+)
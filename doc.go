@@ -69,6 +69,15 @@
 //  Microsoft SQL Server: https://msdn.microsoft.com/en-us/library/cc293623.aspx
 //
 //
+// Generics
+//
+// With Go 1.18+, functions SelectOne, SelectAll, FindOne, FindAll, and FindByPK can be used instead of
+// corresponding Querier methods. They return pointers to generated struct types instead of Struct and Record
+// interface values, so no type assertions are needed:
+//  people, err := reform.SelectAll[Person](DB.Querier, "WHERE name = "+DB.Placeholder(1), "Alice")
+//  project, err := reform.FindByPK[Project](DB.Querier, "baron")
+//
+//
 // Short example
 //
 // This example shows some reform features.
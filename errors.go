@@ -0,0 +1,174 @@
+package reform
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// UnexpectedColumnsError is returned by Querier's methods with column names parameters
+// when some of columns are not present in view.
+type UnexpectedColumnsError struct {
+	View    View     // view or table
+	Columns []string // sorted unexpected column names
+}
+
+// Error returns a string representation of this error.
+func (e *UnexpectedColumnsError) Error() string {
+	return fmt.Sprintf("reform: unexpected columns: %v", e.Columns)
+}
+
+// PKUpdateError is returned by Querier's update methods when primary key column is specified for update.
+type PKUpdateError struct {
+	Table  Table  // table
+	Column string // primary key column name
+}
+
+// Error returns a string representation of this error.
+func (e *PKUpdateError) Error() string {
+	return fmt.Sprintf("reform: will not update PK column: %s", e.Column)
+}
+
+// AffectedRowsError is returned by Querier's methods that update or delete a single row by primary key
+// when several rows were affected, typically because there is no primary key or unique constraint
+// in SQL database table for columns with "pk" label. Those changes are not rolled back.
+type AffectedRowsError struct {
+	Table   Table  // table
+	Command string // UPDATE or DELETE
+	Rows    int64  // number of affected rows
+}
+
+// Error returns a string representation of this error.
+func (e *AffectedRowsError) Error() string {
+	return fmt.Sprintf("reform: %d rows affected by %s by primary key in %s", e.Rows, e.Command, e.Table.Name())
+}
+
+// ErrorKind is a kind of database error classified by Dialect's ClassifyError.
+type ErrorKind int
+
+const (
+	// UnclassifiedError is a kind of errors that are not classified. They are not wrapped in DBError.
+	UnclassifiedError ErrorKind = iota
+
+	// UniqueViolation is a kind of errors caused by unique constraint or primary key violation.
+	UniqueViolation
+
+	// ForeignKeyViolation is a kind of errors caused by foreign key constraint violation.
+	ForeignKeyViolation
+
+	// NotNullViolation is a kind of errors caused by NOT NULL constraint violation.
+	NotNullViolation
+
+	// CheckViolation is a kind of errors caused by CHECK constraint violation.
+	CheckViolation
+
+	// Deadlock is a kind of errors caused by deadlock detection.
+	Deadlock
+
+	// Timeout is a kind of errors caused by statement or lock timeout.
+	Timeout
+)
+
+// String returns a human-readable error kind.
+func (k ErrorKind) String() string {
+	switch k {
+	case UniqueViolation:
+		return "unique violation"
+	case ForeignKeyViolation:
+		return "foreign key violation"
+	case NotNullViolation:
+		return "not-null violation"
+	case CheckViolation:
+		return "check violation"
+	case Deadlock:
+		return "deadlock"
+	case Timeout:
+		return "timeout"
+	default:
+		return "unclassified error"
+	}
+}
+
+// DBError wraps a database driver error classified by Dialect's ClassifyError (see ClassifyErrorDialect).
+// It is returned by Querier's methods that build queries for Views (selectors, finders, insert, update,
+// and delete methods), but not by Exec, Query and QueryRow methods, which return driver errors as is.
+//
+// Use errors.As to get it, and errors.As or Unwrap to get original driver error.
+type DBError struct {
+	Kind       ErrorKind // error kind
+	Constraint string    // constraint name, if available
+	Query      string    // failed query
+	View       View      // view or table used by failed query, may be nil
+	Err        error     // original driver error
+}
+
+// Error returns a string representation of this error.
+func (e *DBError) Error() string {
+	res := "reform: " + e.Kind.String()
+	if e.Constraint != "" {
+		res += fmt.Sprintf(" (constraint %s)", e.Constraint)
+	}
+	if e.View != nil {
+		res += " in " + e.View.Name()
+	}
+	return res + ": " + e.Err.Error()
+}
+
+// Unwrap returns original driver error.
+func (e *DBError) Unwrap() error {
+	return e.Err
+}
+
+// wrapError wraps err in DBError if it is classified by Dialect. Other errors are returned as is.
+func (q *Querier) wrapError(err error, query string, view View) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(*DBError); ok {
+		return err
+	}
+
+	kind, constraint := classifyError(q.Dialect, err)
+	if kind == UnclassifiedError {
+		return err
+	}
+	return &DBError{
+		Kind:       kind,
+		Constraint: constraint,
+		Query:      query,
+		View:       view,
+		Err:        err,
+	}
+}
+
+// exec executes a query built for given view, wrapping classified errors in DBError.
+func (q *Querier) exec(view View, query string, args ...interface{}) (sql.Result, error) {
+	res, err := q.Exec(query, args...)
+	return res, q.wrapError(err, query, view)
+}
+
+// query executes a query built for given view, wrapping classified errors in DBError.
+func (q *Querier) query(view View, query string, args ...interface{}) (*sql.Rows, error) {
+	rows, err := q.Query(query, args...)
+	return rows, q.wrapError(err, query, view)
+}
+
+// queryRow executes a query built for given view and scans the first row to dest,
+// wrapping classified errors in DBError.
+func (q *Querier) queryRow(view View, query string, args []interface{}, dest ...interface{}) error {
+	err := q.QueryRow(query, args...).Scan(dest...)
+	return q.wrapError(err, query, view)
+}
+
+// read executes a read-only query built for given view on Querier's reader,
+// wrapping classified errors in DBError.
+func (q *Querier) read(view View, query string, args ...interface{}) (*sql.Rows, error) {
+	rows, err := q.queryOn(q.reader(), query, args...)
+	return rows, q.wrapError(err, query, view)
+}
+
+// readRow executes a read-only query built for given view on Querier's reader and scans the first row to dest,
+// wrapping classified errors in DBError.
+func (q *Querier) readRow(view View, query string, args []interface{}, dest ...interface{}) error {
+	err := q.queryRowOn(q.reader(), query, args...).Scan(dest...)
+	return q.wrapError(err, query, view)
+}
@@ -0,0 +1,175 @@
+package reform_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	mssqlDriver "github.com/denisenkom/go-mssqldb"
+	mysqlDriver "github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx"
+	"github.com/lib/pq"
+	sqlite3Driver "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gopkg.in/reform.v1"
+	"gopkg.in/reform.v1/dialects/mssql" //nolint:staticcheck
+	"gopkg.in/reform.v1/dialects/mysql"
+	"gopkg.in/reform.v1/dialects/postgresql"
+	"gopkg.in/reform.v1/dialects/sqlite3"
+	"gopkg.in/reform.v1/dialects/sqlserver"
+	. "gopkg.in/reform.v1/internal/test/models"
+)
+
+func TestClassifyError(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		err        error
+		kind       reform.ErrorKind
+		constraint string
+	}
+
+	mssqlCases := []testCase{
+		{mssqlDriver.Error{Number: 2627, Message: "Violation of UNIQUE KEY constraint 'UQ_i'. Cannot insert duplicate key."}, reform.UniqueViolation, "UQ_i"},
+		{mssqlDriver.Error{Number: 2601, Message: "Cannot insert duplicate key row in object 'dbo.t' with unique index 'IX_i'."}, reform.UniqueViolation, "IX_i"},
+		{mssqlDriver.Error{Number: 547, Message: `The INSERT statement conflicted with the FOREIGN KEY constraint "FK_p".`}, reform.ForeignKeyViolation, "FK_p"},
+		{mssqlDriver.Error{Number: 547, Message: `The INSERT statement conflicted with the CHECK constraint "CK_i".`}, reform.CheckViolation, "CK_i"},
+		{mssqlDriver.Error{Number: 515}, reform.NotNullViolation, ""},
+		{mssqlDriver.Error{Number: 1205}, reform.Deadlock, ""},
+		{mssqlDriver.Error{Number: 1222}, reform.Timeout, ""},
+		{mssqlDriver.Error{Number: 208}, reform.UnclassifiedError, ""},
+	}
+
+	for d, cases := range map[reform.Dialect][]testCase{
+		postgresql.Dialect: {
+			{&pq.Error{Code: "23505", Constraint: "constraints_i_key"}, reform.UniqueViolation, "constraints_i_key"},
+			{pgx.PgError{Code: "23505", ConstraintName: "constraints_i_key"}, reform.UniqueViolation, "constraints_i_key"},
+			{&pq.Error{Code: "23503", Constraint: "fk"}, reform.ForeignKeyViolation, "fk"},
+			{&pq.Error{Code: "23502"}, reform.NotNullViolation, ""},
+			{pgx.PgError{Code: "23514", ConstraintName: "check"}, reform.CheckViolation, "check"},
+			{&pq.Error{Code: "40P01"}, reform.Deadlock, ""},
+			{&pq.Error{Code: "57014"}, reform.Timeout, ""},
+			{&pq.Error{Code: "42P01"}, reform.UnclassifiedError, ""},
+		},
+		mysql.Dialect: {
+			{&mysqlDriver.MySQLError{Number: 1062, Message: "Duplicate entry '1' for key 'constraints.i'"}, reform.UniqueViolation, "constraints.i"},
+			{&mysqlDriver.MySQLError{Number: 1452, Message: "Cannot add or update a child row: a foreign key constraint fails " +
+				"(`reform`.`person_project`, CONSTRAINT `person_project_ibfk_1` FOREIGN KEY (`person_id`) REFERENCES `people` (`id`))"},
+				reform.ForeignKeyViolation, "person_project_ibfk_1"},
+			{&mysqlDriver.MySQLError{Number: 1048}, reform.NotNullViolation, ""},
+			{&mysqlDriver.MySQLError{Number: 3819, Message: "Check constraint 'positive' is violated."}, reform.CheckViolation, "positive"},
+			{&mysqlDriver.MySQLError{Number: 1213}, reform.Deadlock, ""},
+			{&mysqlDriver.MySQLError{Number: 1205}, reform.Timeout, ""},
+			{&mysqlDriver.MySQLError{Number: 1146}, reform.UnclassifiedError, ""},
+		},
+		sqlite3.Dialect: {
+			{sqlite3Driver.Error{Code: sqlite3Driver.ErrConstraint, ExtendedCode: sqlite3Driver.ErrConstraintUnique}, reform.UniqueViolation, ""},
+			{sqlite3Driver.Error{Code: sqlite3Driver.ErrConstraint, ExtendedCode: sqlite3Driver.ErrConstraintPrimaryKey}, reform.UniqueViolation, ""},
+			{sqlite3Driver.Error{Code: sqlite3Driver.ErrConstraint, ExtendedCode: sqlite3Driver.ErrConstraintForeignKey}, reform.ForeignKeyViolation, ""},
+			{sqlite3Driver.Error{Code: sqlite3Driver.ErrConstraint, ExtendedCode: sqlite3Driver.ErrConstraintNotNull}, reform.NotNullViolation, ""},
+			{sqlite3Driver.Error{Code: sqlite3Driver.ErrConstraint, ExtendedCode: sqlite3Driver.ErrConstraintCheck}, reform.CheckViolation, ""},
+			{sqlite3Driver.Error{Code: sqlite3Driver.ErrBusy}, reform.Timeout, ""},
+			{sqlite3Driver.Error{Code: sqlite3Driver.ErrError}, reform.UnclassifiedError, ""},
+		},
+		mssql.Dialect:     mssqlCases, //nolint:staticcheck
+		sqlserver.Dialect: mssqlCases,
+	} {
+		dialect := d.(reform.ClassifyErrorDialect)
+		for _, tc := range cases {
+			for _, err := range []error{tc.err, fmt.Errorf("wrapped: %w", tc.err)} {
+				kind, constraint := dialect.ClassifyError(err)
+				assert.Equal(t, tc.kind, kind, "%s: %#v", dialect, tc.err)
+				assert.Equal(t, tc.constraint, constraint, "%s: %#v", dialect, tc.err)
+			}
+		}
+
+		kind, _ := dialect.ClassifyError(errors.New("23505 1062 2627"))
+		assert.Equal(t, reform.UnclassifiedError, kind)
+	}
+}
+
+func TestDBError(t *testing.T) {
+	db, tx := setupTX(t)
+	defer teardown(t, db)
+	defer func() {
+		assert.NoError(t, tx.Rollback())
+	}()
+
+	q := tx.Querier
+
+	require.NoError(t, q.Insert(&Constraints{I: 1, ID: "first"}))
+	err := q.Insert(&Constraints{I: 1, ID: "second"})
+	require.Error(t, err)
+
+	var dbErr *reform.DBError
+	require.True(t, errors.As(err, &dbErr), "%#v", err)
+	assert.Equal(t, reform.UniqueViolation, dbErr.Kind)
+	assert.Equal(t, ConstraintsTable, dbErr.View)
+	assert.True(t, strings.HasPrefix(dbErr.Query, "INSERT INTO "), "%s", dbErr.Query)
+	assert.Equal(t, dbErr.Err, errors.Unwrap(err))
+	assert.True(t, strings.HasPrefix(err.Error(), "reform: unique violation"), "%s", err)
+	if q.Dialect == postgresql.Dialect {
+		assert.Equal(t, "constraints_i_key", dbErr.Constraint)
+	}
+
+	// errors are not wrapped for dialects without ClassifyErrorDialect
+	minimal := *q
+	minimal.Dialect = minimalDialect{q.Dialect}
+	err = minimal.Insert(&Constraints{I: 1, ID: "third"})
+	require.Error(t, err)
+	assert.False(t, errors.As(err, &dbErr), "%#v", err)
+}
+
+func TestColumnErrors(t *testing.T) {
+	db, tx := setupTX(t)
+	defer teardown(t, db)
+	defer func() {
+		assert.NoError(t, tx.Rollback())
+	}()
+
+	q := tx.Querier
+
+	person := &Person{ID: 102, Name: "Elfrieda Abbott"}
+	err := q.UpdateColumns(person, "name", "foo", "bar")
+	var columnsErr *reform.UnexpectedColumnsError
+	require.True(t, errors.As(err, &columnsErr), "%#v", err)
+	assert.Equal(t, PersonTable, columnsErr.View)
+	assert.Equal(t, []string{"bar", "foo"}, columnsErr.Columns)
+	assert.EqualError(t, err, "reform: unexpected columns: [bar foo]")
+
+	err = q.UpdateColumns(person, "name", "id")
+	var pkErr *reform.PKUpdateError
+	require.True(t, errors.As(err, &pkErr), "%#v", err)
+	assert.Equal(t, PersonTable, pkErr.Table)
+	assert.Equal(t, "id", pkErr.Column)
+	assert.EqualError(t, err, "reform: will not update PK column: id")
+
+	// commands by primary key affecting several rows
+	mdb := reform.NewDBFromInterface(multiRowsDB{db.DBInterface()}, db.Dialect, db.Logger)
+	var rowsErr *reform.AffectedRowsError
+	err = mdb.Update(person)
+	require.True(t, errors.As(err, &rowsErr), "%#v", err)
+	assert.Equal(t, &reform.AffectedRowsError{Table: PersonTable, Command: "UPDATE", Rows: 2}, rowsErr)
+	err = mdb.Delete(&Document{ID: 1})
+	require.True(t, errors.As(err, &rowsErr), "%#v", err)
+	assert.Equal(t, &reform.AffectedRowsError{Table: DocumentTable, Command: "UPDATE", Rows: 2}, rowsErr)
+	err = mdb.HardDelete(person)
+	require.True(t, errors.As(err, &rowsErr), "%#v", err)
+	assert.Equal(t, &reform.AffectedRowsError{Table: PersonTable, Command: "DELETE", Rows: 2}, rowsErr)
+	assert.EqualError(t, err, "reform: 2 rows affected by DELETE by primary key in people")
+}
+
+// multiRowsDB is a DBInterface that doesn't execute commands and reports two affected rows for each of them.
+type multiRowsDB struct {
+	reform.DBInterface
+}
+
+func (multiRowsDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return driver.RowsAffected(2), nil
+}
@@ -0,0 +1,119 @@
+//go:build go1.18
+// +build go1.18
+
+package reform
+
+import (
+	"database/sql"
+)
+
+// StructPointer is a constraint for a pointer to generated struct type T.
+// It is satisfied by *T for any struct type T generated by reform.
+type StructPointer[T any] interface {
+	*T
+	Struct
+}
+
+// RecordPointer is a constraint for a pointer to generated struct type T with primary key.
+// It is satisfied by *T for any struct type T with primary key generated by reform.
+type RecordPointer[T any] interface {
+	*T
+	Record
+}
+
+// SelectOne queries T's View with tail and args and scans first result to new *T.
+// If *T implements AfterFinder, it also calls AfterFind().
+//
+// It works like Querier.SelectOneFrom, but returns *T instead of Struct:
+//
+//  person, err := reform.SelectOne[Person](q, "WHERE name = "+q.Placeholder(1), "Alice")
+//
+// If there are no rows in result, it returns nil, ErrNoRows. It also may return QueryRow(), Scan()
+// and AfterFinder errors.
+func SelectOne[T any, PT StructPointer[T]](q *Querier, tail string, args ...interface{}) (*T, error) {
+	str := new(T)
+	if err := q.SelectOneTo(PT(str), tail, args...); err != nil {
+		return nil, err
+	}
+	return str, nil
+}
+
+// SelectAll queries T's View with tail and args and returns a slice of new *T.
+// If *T implements AfterFinder, it also calls AfterFind().
+//
+// It works like Querier.SelectAllFrom, but returns []*T instead of []Struct:
+//
+//  people, err := reform.SelectAll[Person](q, "ORDER BY id")
+//
+// In case of query error slice will be nil. If error is encountered during iteration,
+// partial result and error will be returned. Error is never ErrNoRows.
+func SelectAll[T any, PT StructPointer[T]](q *Querier, tail string, args ...interface{}) (structs []*T, err error) {
+	var rows *sql.Rows
+	rows, err = q.SelectRows(PT(new(T)).View(), tail, args...)
+	if err != nil {
+		return
+	}
+	defer func() {
+		e := rows.Close()
+		if err == nil {
+			err = e
+		}
+	}()
+
+	for {
+		str := new(T)
+		if err = q.NextRow(PT(str), rows); err != nil {
+			break
+		}
+
+		structs = append(structs, str)
+	}
+	if err == ErrNoRows {
+		err = nil
+	}
+	return
+}
+
+// FindOne queries T's View with column and arg and scans first result to new *T.
+// If *T implements AfterFinder, it also calls AfterFind().
+//
+// It works like Querier.FindOneFrom, but returns *T instead of Struct.
+//
+// If there are no rows in result, it returns nil, ErrNoRows. It also may return QueryRow(), Scan()
+// and AfterFinder errors.
+func FindOne[T any, PT StructPointer[T]](q *Querier, column string, arg interface{}) (*T, error) {
+	str := new(T)
+	if err := q.FindOneTo(PT(str), column, arg); err != nil {
+		return nil, err
+	}
+	return str, nil
+}
+
+// FindAll queries T's View with column and args and returns a slice of new *T.
+// If *T implements AfterFinder, it also calls AfterFind().
+//
+// It works like Querier.FindAllFrom, but returns []*T instead of []Struct.
+//
+// In case of query error slice will be nil. If error is encountered during iteration,
+// partial result and error will be returned. Error is never ErrNoRows.
+func FindAll[T any, PT StructPointer[T]](q *Querier, column string, args ...interface{}) ([]*T, error) {
+	tail := q.findAllTail(PT(new(T)).View(), column, len(args))
+	return SelectAll[T, PT](q, tail, args...)
+}
+
+// FindByPK queries T's Table with primary key and scans first result to new *T.
+// If *T implements AfterFinder, it also calls AfterFind().
+//
+// It works like Querier.FindByPrimaryKeyFrom, but returns *T instead of Record:
+//
+//  project, err := reform.FindByPK[Project](q, "baron")
+//
+// If there are no rows in result, it returns nil, ErrNoRows. It also may return QueryRow(), Scan()
+// and AfterFinder errors.
+func FindByPK[T any, PT RecordPointer[T]](q *Querier, pk interface{}) (*T, error) {
+	record := new(T)
+	if err := q.FindByPrimaryKeyTo(PT(record), pk); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
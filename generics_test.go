@@ -0,0 +1,82 @@
+//go:build go1.18
+// +build go1.18
+
+package reform_test
+
+import (
+	"testing"
+
+	"github.com/AlekSi/pointer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gopkg.in/reform.v1"
+	. "gopkg.in/reform.v1/internal/test/models"
+)
+
+func TestGenerics(t *testing.T) {
+	db, tx := setupTX(t)
+	defer teardown(t, db)
+	defer func() {
+		assert.NoError(t, tx.Rollback())
+	}()
+
+	q := tx.Querier
+	person102 := &Person{ID: 102, GroupID: pointer.ToInt32(65534), Name: "Elfrieda Abbott", Email: pointer.ToString("elfrieda_abbott@example.org"), CreatedAt: personCreated}
+	person103 := &Person{ID: 103, GroupID: pointer.ToInt32(65534), Name: "Elfrieda Abbott", CreatedAt: personCreated}
+
+	t.Run("SelectOne", func(t *testing.T) {
+		person, err := reform.SelectOne[Person](q, "WHERE name = "+q.Placeholder(1)+" ORDER BY id", "Elfrieda Abbott")
+		require.NoError(t, err)
+		assert.Equal(t, person102, person)
+
+		person, err = reform.SelectOne[Person](q, "WHERE id IS NULL")
+		assert.Nil(t, person)
+		assert.Equal(t, reform.ErrNoRows, err)
+	})
+
+	t.Run("SelectAll", func(t *testing.T) {
+		people, err := reform.SelectAll[Person](q, "WHERE name = "+q.Placeholder(1)+" ORDER BY id", "Elfrieda Abbott")
+		require.NoError(t, err)
+		assert.Equal(t, []*Person{person102, person103}, people)
+
+		projects, err := reform.SelectAll[Project](q, "WHERE id IS NULL")
+		assert.Nil(t, projects)
+		assert.NoError(t, err)
+
+		projects, err = reform.SelectAll[Project](q, "WHERE invalid_tail")
+		assert.Nil(t, projects)
+		assert.Error(t, err)
+		assert.NotEqual(t, reform.ErrNoRows, err)
+	})
+
+	t.Run("FindOne", func(t *testing.T) {
+		project, err := reform.FindOne[Project](q, "id", "queen")
+		require.NoError(t, err)
+		assert.Equal(t, &Project{ID: "queen", Name: "Thirsty Queen", Start: queenStart}, project)
+
+		project, err = reform.FindOne[Project](q, "id", "no_such_project")
+		assert.Nil(t, project)
+		assert.Equal(t, reform.ErrNoRows, err)
+	})
+
+	t.Run("FindAll", func(t *testing.T) {
+		people, err := reform.FindAll[Person](q, "id", 102, 103)
+		require.NoError(t, err)
+		assert.Equal(t, []*Person{person102, person103}, people)
+
+		pp, err := reform.FindAll[PersonProject](q, "project_id", "no_such_project")
+		assert.Nil(t, pp)
+		assert.NoError(t, err)
+	})
+
+	t.Run("FindByPK", func(t *testing.T) {
+		person, err := reform.FindByPK[Person](q, 102)
+		require.NoError(t, err)
+		assert.Equal(t, person102, person)
+
+		person, err = reform.FindByPK[Person](q, -1)
+		assert.Nil(t, person)
+		assert.Equal(t, reform.ErrNoRows, err)
+	})
+}
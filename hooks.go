@@ -0,0 +1,83 @@
+package reform
+
+// Functions in this file call context-aware hooks if they are implemented, and plain hooks otherwise.
+
+func (q *Querier) callBeforeInsert(str Struct) error {
+	if h, ok := str.(BeforeInserterContext); ok {
+		return h.BeforeInsertContext(q.ctx, q)
+	}
+	if h, ok := str.(BeforeInserter); ok {
+		return h.BeforeInsert()
+	}
+	return nil
+}
+
+func (q *Querier) callAfterInsert(str Struct) error {
+	if h, ok := str.(AfterInserterContext); ok {
+		return h.AfterInsertContext(q.ctx, q)
+	}
+	if h, ok := str.(AfterInserter); ok {
+		return h.AfterInsert()
+	}
+	return nil
+}
+
+func (q *Querier) callBeforeUpdate(str Struct) error {
+	if h, ok := str.(BeforeUpdaterContext); ok {
+		return h.BeforeUpdateContext(q.ctx, q)
+	}
+	if h, ok := str.(BeforeUpdater); ok {
+		return h.BeforeUpdate()
+	}
+	return nil
+}
+
+func (q *Querier) callAfterUpdate(str Struct) error {
+	if h, ok := str.(AfterUpdaterContext); ok {
+		return h.AfterUpdateContext(q.ctx, q)
+	}
+	if h, ok := str.(AfterUpdater); ok {
+		return h.AfterUpdate()
+	}
+	return nil
+}
+
+func (q *Querier) callAfterSave(str Struct) error {
+	if h, ok := str.(AfterSaverContext); ok {
+		return h.AfterSaveContext(q.ctx, q)
+	}
+	if h, ok := str.(AfterSaver); ok {
+		return h.AfterSave()
+	}
+	return nil
+}
+
+func (q *Querier) callBeforeDelete(str Struct) error {
+	if h, ok := str.(BeforeDeleterContext); ok {
+		return h.BeforeDeleteContext(q.ctx, q)
+	}
+	if h, ok := str.(BeforeDeleter); ok {
+		return h.BeforeDelete()
+	}
+	return nil
+}
+
+func (q *Querier) callAfterDelete(str Struct) error {
+	if h, ok := str.(AfterDeleterContext); ok {
+		return h.AfterDeleteContext(q.ctx, q)
+	}
+	if h, ok := str.(AfterDeleter); ok {
+		return h.AfterDelete()
+	}
+	return nil
+}
+
+func (q *Querier) callAfterFind(str Struct) error {
+	if h, ok := str.(AfterFinderContext); ok {
+		return h.AfterFindContext(q.ctx, q)
+	}
+	if h, ok := str.(AfterFinder); ok {
+		return h.AfterFind()
+	}
+	return nil
+}
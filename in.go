@@ -0,0 +1,249 @@
+package reform
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// InArgs is a list of values expanded into placeholders for IN condition. It is created by In.
+type InArgs struct {
+	values []interface{}
+}
+
+// In returns InArgs for given slice or array. When it is passed as an argument for a placeholder
+// to any Querier's method, that placeholder is replaced with the right number of Dialect's placeholders,
+// and subsequent placeholders are renumbered (for dialects with numbered placeholders):
+//
+//	tail := fmt.Sprintf("WHERE id IN (%s) AND name = %s", q.Placeholder(1), q.Placeholder(2))
+//	people, err := q.SelectAllFrom(PersonTable, tail, reform.In(ids), name)
+//
+// Empty lists are replaced with NULL, so both "IN" and "NOT IN" conditions do not match any rows.
+// Placeholders are not recognized inside quoted strings and identifiers, comments
+// and PostgreSQL's dollar-quoted strings.
+func In(slice interface{}) InArgs {
+	values, ok := sliceValues(slice)
+	if !ok {
+		panic(fmt.Sprintf("reform: In expects slice or array, got %T", slice))
+	}
+	return InArgs{values: values}
+}
+
+// Value implements driver.Valuer. It always returns error, as InArgs should be expanded by Querier
+// before passing them to the driver.
+func (InArgs) Value() (driver.Value, error) {
+	return nil, errors.New("reform: In argument is not bound to a placeholder")
+}
+
+// sliceValues returns elements of given slice or array (except []byte), and true, or nil and false.
+func sliceValues(v interface{}) ([]interface{}, bool) {
+	if _, ok := v.([]byte); ok {
+		return nil, false
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+
+	res := make([]interface{}, rv.Len())
+	for i := range res {
+		res[i] = rv.Index(i).Interface()
+	}
+	return res, true
+}
+
+// placeholderToken is a placeholder found in query.
+type placeholderToken struct {
+	start, end int // position in query
+	arg        int // argument index
+}
+
+// placeholderTokens returns placeholders in query outside of quoted strings and identifiers,
+// comments and dollar-quoted strings.
+func (q *Querier) placeholderTokens(query string) []placeholderToken {
+	// numbered placeholders are "$1", "@P1", etc.; others are "?"
+	var prefix string
+	numbered := q.Placeholder(1) != q.Placeholder(2)
+	if numbered {
+		prefix = strings.TrimSuffix(q.Placeholder(1), "1")
+	}
+
+	backslash, dollar := q.stringSyntax()
+
+	var res []placeholderToken
+	for i := 0; i < len(query); i++ {
+		if end := skippedEnd(query, i, backslash, dollar); end >= 0 {
+			i = end
+			continue
+		}
+
+		t := placeholderToken{start: i}
+		switch {
+		case numbered && strings.HasPrefix(query[i:], prefix):
+			end := i + len(prefix)
+			for end < len(query) && query[end] >= '0' && query[end] <= '9' {
+				end++
+			}
+			if end == i+len(prefix) {
+				continue
+			}
+			n := 0
+			for _, d := range query[i+len(prefix) : end] {
+				n = n*10 + int(d-'0')
+			}
+			t.arg = n - 1
+			t.end = end
+
+		case !numbered && query[i] == '?':
+			t.arg = len(res)
+			t.end = i + 1
+
+		default:
+			continue
+		}
+
+		res = append(res, t)
+		i = t.end - 1
+	}
+	return res
+}
+
+// expandIn expands InArgs in query, and returns new query and arguments.
+// Placeholders and arguments that can't be matched are left as is.
+func (q *Querier) expandIn(query string, args []interface{}) (string, []interface{}) {
+	var hasIn bool
+	for _, arg := range args {
+		if _, ok := arg.(InArgs); ok {
+			hasIn = true
+			break
+		}
+	}
+	if !hasIn {
+		return query, args
+	}
+
+	tokens := q.placeholderTokens(query)
+
+	// values of expanded arguments
+	expanded := make([]bool, len(args))
+	values := make([][]interface{}, len(args))
+	var found bool
+	for _, t := range tokens {
+		if t.arg < 0 || t.arg >= len(args) || expanded[t.arg] {
+			continue
+		}
+		if in, ok := args[t.arg].(InArgs); ok {
+			expanded[t.arg], values[t.arg] = true, in.values
+			found = true
+		}
+	}
+	if !found {
+		return query, args
+	}
+
+	// new arguments and new index of the first placeholder of each argument
+	starts := make([]int, len(args))
+	var newArgs []interface{}
+	for i, arg := range args {
+		starts[i] = len(newArgs) + 1
+		if expanded[i] {
+			newArgs = append(newArgs, values[i]...)
+		} else {
+			newArgs = append(newArgs, arg)
+		}
+	}
+
+	var res strings.Builder
+	var last int
+	for _, t := range tokens {
+		if t.arg < 0 || t.arg >= len(args) {
+			continue
+		}
+		res.WriteString(query[last:t.start])
+		last = t.end
+
+		switch {
+		case !expanded[t.arg]:
+			res.WriteString(q.Placeholder(starts[t.arg]))
+		case len(values[t.arg]) == 0:
+			res.WriteString("NULL")
+		default:
+			res.WriteString(strings.Join(q.Placeholders(starts[t.arg], len(values[t.arg])), ", "))
+		}
+	}
+	res.WriteString(query[last:])
+
+	return res.String(), newArgs
+}
+
+// stringSyntax returns whether Dialect uses backslash escapes in strings (MySQL)
+// and dollar-quoted strings (PostgreSQL).
+func (q *Querier) stringSyntax() (backslash, dollar bool) {
+	backslash = strings.HasPrefix(q.QuoteIdentifier(""), "`")
+	dollar = q.Placeholder(1) == "$1"
+	return
+}
+
+// skippedEnd returns index of the last byte of quoted string or identifier, comment or dollar-quoted string
+// (if dollar is true) starting at s[i], or -1 if there is none. Backslash escapes quotes in strings
+// if backslash is true. Unclosed ones end at the last byte of s.
+func skippedEnd(s string, i int, backslash, dollar bool) int {
+	c := s[i]
+	switch {
+	case c == '\'' || c == '"' || c == '`':
+		if !backslash || c == '`' {
+			return quotedEnd(s, i)
+		}
+		for j := i + 1; j < len(s); j++ {
+			switch s[j] {
+			case '\\':
+				j++
+			case c:
+				return j
+			}
+		}
+		return len(s) - 1
+
+	case strings.HasPrefix(s[i:], "--"):
+		if end := strings.IndexByte(s[i:], '\n'); end >= 0 {
+			return i + end
+		}
+		return len(s) - 1
+
+	case strings.HasPrefix(s[i:], "/*"):
+		if end := strings.Index(s[i+2:], "*/"); end >= 0 {
+			return i + 2 + end + 1
+		}
+		return len(s) - 1
+
+	case dollar && c == '$' && (i == 0 || !isNamePart(s[i-1]) && s[i-1] != '$'):
+		// "$$" or "$tag$"
+		j := i + 1
+		if j < len(s) && isNameStart(s[j]) {
+			for j < len(s) && isNamePart(s[j]) {
+				j++
+			}
+		}
+		if j >= len(s) || s[j] != '$' {
+			return -1
+		}
+		tag := s[i : j+1]
+		if end := strings.Index(s[j+1:], tag); end >= 0 {
+			return j + end + len(tag)
+		}
+		return len(s) - 1
+	}
+
+	return -1
+}
+
+// quotedEnd returns index of closing quote for quoted string or identifier starting at s[i],
+// or the last index of s if it is not closed. Doubled quotes are handled as two adjacent strings.
+func quotedEnd(s string, i int) int {
+	if end := strings.IndexByte(s[i+1:], s[i]); end >= 0 {
+		return i + 1 + end
+	}
+	return len(s) - 1
+}
@@ -0,0 +1,99 @@
+package internal
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+)
+
+// SQLState returns SQLSTATE code of PostgreSQL driver error (lib/pq or pgx) in err's chain, or empty string.
+// It does not import drivers.
+func SQLState(err error) string {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		switch e := e.(type) {
+		case interface{ SQLState() string }: // pgx
+			return e.SQLState()
+		case interface{ Get(k byte) string }: // lib/pq
+			return e.Get('C')
+		}
+	}
+	return ""
+}
+
+// ErrorNumber returns vendor error number of MySQL, SQL Server or SQLite driver error in err's chain,
+// and true if found. It does not import drivers.
+func ErrorNumber(err error) (int64, bool) {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if e, ok := e.(interface{ SQLErrorNumber() int32 }); ok { // go-mssqldb
+			return int64(e.SQLErrorNumber()), true
+		}
+
+		// go-sql-driver/mysql's MySQLError.Number, mattn/go-sqlite3's Error.Code
+		for _, name := range []string{"Number", "Code"} {
+			if n, ok := integerField(e, name); ok {
+				return n, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// ExtendedErrorNumber returns extended error code of SQLite driver error in err's chain, and true if found.
+// It does not import drivers.
+func ExtendedErrorNumber(err error) (int64, bool) {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if n, ok := integerField(e, "ExtendedCode"); ok { // mattn/go-sqlite3
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+// ConstraintName returns constraint name of PostgreSQL driver error (lib/pq or pgx) in err's chain, or empty string.
+// It does not import drivers.
+func ConstraintName(err error) string {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if e, ok := e.(interface{ Get(k byte) string }); ok { // lib/pq
+			return e.Get('n')
+		}
+
+		v := reflect.Indirect(reflect.ValueOf(e))
+		if v.Kind() != reflect.Struct {
+			continue
+		}
+		if f := v.FieldByName("ConstraintName"); f.IsValid() && f.Kind() == reflect.String { // pgx
+			return f.String()
+		}
+	}
+	return ""
+}
+
+// MatchMessage returns the first submatch of re in err's message, or empty string.
+func MatchMessage(err error, re *regexp.Regexp) string {
+	if m := re.FindStringSubmatch(err.Error()); len(m) > 1 {
+		return m[1]
+	}
+	return ""
+}
+
+// integerField returns a value of exported integer field with given name of struct or pointer to struct.
+func integerField(e error, name string) (int64, bool) {
+	v := reflect.Indirect(reflect.ValueOf(e))
+	if v.Kind() != reflect.Struct {
+		return 0, false
+	}
+
+	f, ok := v.Type().FieldByName(name)
+	if !ok || f.PkgPath != "" {
+		return 0, false
+	}
+	fv := v.FieldByIndex(f.Index)
+	switch fv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return fv.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(fv.Uint()), true
+	default:
+		return 0, false
+	}
+}
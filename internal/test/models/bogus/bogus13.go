@@ -0,0 +1,8 @@
+package bogus
+
+//go:generate reform
+
+// Bogus13 is used for testing. reform:bogus
+type Bogus13 struct {
+	Bogus int64 `reform:"bogus,autocreate"` // non-time field with "reform:" tag and autocreate label should generate error
+}
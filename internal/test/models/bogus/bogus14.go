@@ -0,0 +1,8 @@
+package bogus
+
+//go:generate reform
+
+// Bogus14 is used for testing. reform:bogus
+type Bogus14 struct {
+	Bogus int32 `reform:"bogus,fk=groups"` // field with "reform:" tag and fk label without referenced column should generate error
+}
@@ -0,0 +1,8 @@
+package bogus
+
+//go:generate reform
+
+// Bogus15 is used for testing. reform:bogus
+type Bogus15 struct {
+	Bogus int32 `reform:"bogus,pk,pk"` // field with "reform:" tag and duplicate label should generate error
+}
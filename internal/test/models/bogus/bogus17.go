@@ -0,0 +1,8 @@
+package bogus
+
+//go:generate reform
+
+// Bogus17 is used for testing. reform:bogus
+type Bogus17 struct {
+	Bogus int32 `reform:"bogus,fk=groups.id,fk=users.id"` // field with "reform:" tag and several fk labels should generate error
+}
@@ -58,6 +58,11 @@ func (v *extraTableType) PKColumnIndex() uint {
 	return uint(v.s.PKFieldIndex)
 }
 
+// PKColumnIndexes returns a new slice of indexes of all primary key columns for that table in SQL database.
+func (v *extraTableType) PKColumnIndexes() []uint {
+	return []uint{0}
+}
+
 // ExtraTable represents extra view or table in SQL database.
 var ExtraTable = &extraTableType{
 	s: parse.StructInfo{
@@ -77,7 +82,8 @@ var ExtraTable = &extraTableType{
 			{Name: "BytesT", Type: "Bytes", Column: "bytest"},
 			{Name: "Uint8sT", Type: "Uint8s", Column: "uint8st"},
 		},
-		PKFieldIndex: 0,
+		PKFieldIndex:   0,
+		PKFieldIndexes: []int{0},
 	},
 	z: new(Extra).Values(),
 }
@@ -160,6 +166,22 @@ func (s *Extra) PKPointer() interface{} {
 	return &s.ID
 }
 
+// PKValues returns a slice of primary key field values for that record.
+// Returned interface{} values are never untyped nils.
+func (s *Extra) PKValues() []interface{} {
+	return []interface{}{
+		s.ID,
+	}
+}
+
+// PKPointers returns a slice of pointers to primary key fields for that record.
+// Returned interface{} values are never untyped nils.
+func (s *Extra) PKPointers() []interface{} {
+	return []interface{}{
+		&s.ID,
+	}
+}
+
 // HasPK returns true if record has non-zero primary key set, false otherwise.
 func (s *Extra) HasPK() bool {
 	return s.ID != ExtraTable.z[ExtraTable.s.PKFieldIndex]
@@ -218,6 +240,11 @@ func (v *notExportedTableType) PKColumnIndex() uint {
 	return uint(v.s.PKFieldIndex)
 }
 
+// PKColumnIndexes returns a new slice of indexes of all primary key columns for that table in SQL database.
+func (v *notExportedTableType) PKColumnIndexes() []uint {
+	return []uint{0}
+}
+
 // notExportedTable represents not_exported view or table in SQL database.
 var notExportedTable = &notExportedTableType{
 	s: parse.StructInfo{
@@ -226,7 +253,8 @@ var notExportedTable = &notExportedTableType{
 		Fields: []parse.FieldInfo{
 			{Name: "ID", Type: "string", Column: "id"},
 		},
-		PKFieldIndex: 0,
+		PKFieldIndex:   0,
+		PKFieldIndexes: []int{0},
 	},
 	z: new(notExported).Values(),
 }
@@ -276,6 +304,22 @@ func (s *notExported) PKPointer() interface{} {
 	return &s.ID
 }
 
+// PKValues returns a slice of primary key field values for that record.
+// Returned interface{} values are never untyped nils.
+func (s *notExported) PKValues() []interface{} {
+	return []interface{}{
+		s.ID,
+	}
+}
+
+// PKPointers returns a slice of pointers to primary key fields for that record.
+// Returned interface{} values are never untyped nils.
+func (s *notExported) PKPointers() []interface{} {
+	return []interface{}{
+		&s.ID,
+	}
+}
+
 // HasPK returns true if record has non-zero primary key set, false otherwise.
 func (s *notExported) HasPK() bool {
 	return s.ID != notExportedTable.z[notExportedTable.s.PKFieldIndex]
@@ -103,8 +103,8 @@ func (p *Project) AfterFind() error {
 
 // PersonProject represents row in table person_project. reform:person_project
 type PersonProject struct {
-	PersonID  int32  `reform:"person_id"`
-	ProjectID string `reform:"project_id"`
+	PersonID  int32  `reform:"person_id,fk=people.id"`
+	ProjectID string `reform:"project_id,fk=projects.id"`
 }
 
 // reform:id_only
@@ -125,6 +125,35 @@ type CompositePk struct {
 	J    string `reform:"j"`
 }
 
+//reform:composite_pk
+type CompositePkRecord struct {
+	I    int32  `reform:"i,pk"`
+	Name string `reform:"name"`
+	J    string `reform:"j,pk"`
+}
+
+//reform:documents
+type Document struct {
+	ID        int32      `reform:"id,pk"`
+	Title     string     `reform:"title"`
+	Version   int32      `reform:"version,version"`
+	CreatedAt time.Time  `reform:"created_at,autocreate"`
+	UpdatedAt *time.Time `reform:"updated_at,autoupdate"`
+	DeletedAt *time.Time `reform:"deleted_at,softdelete"`
+}
+
+// AfterFind converts to UTC CreatedAt, UpdatedAt and DeletedAt.
+func (d *Document) AfterFind() error {
+	d.CreatedAt = d.CreatedAt.UTC()
+	if d.UpdatedAt != nil {
+		d.UpdatedAt = pointer.ToTime(d.UpdatedAt.UTC())
+	}
+	if d.DeletedAt != nil {
+		d.DeletedAt = pointer.ToTime(d.DeletedAt.UTC())
+	}
+	return nil
+}
+
 //reform:legacy.people
 type LegacyPerson struct {
 	ID   int32   `reform:"id,pk"`
@@ -139,4 +168,5 @@ var (
 	_ reform.BeforeInserter = (*Project)(nil)
 	_ reform.BeforeUpdater  = (*Project)(nil)
 	_ reform.AfterFinder    = (*Project)(nil)
+	_ reform.AfterFinder    = (*Document)(nil)
 )
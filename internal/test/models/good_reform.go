@@ -52,6 +52,11 @@ func (v *personTableType) PKColumnIndex() uint {
 	return uint(v.s.PKFieldIndex)
 }
 
+// PKColumnIndexes returns a new slice of indexes of all primary key columns for that table in SQL database.
+func (v *personTableType) PKColumnIndexes() []uint {
+	return []uint{0}
+}
+
 // PersonTable represents people view or table in SQL database.
 var PersonTable = &personTableType{
 	s: parse.StructInfo{
@@ -65,7 +70,8 @@ var PersonTable = &personTableType{
 			{Name: "CreatedAt", Type: "time.Time", Column: "created_at"},
 			{Name: "UpdatedAt", Type: "*time.Time", Column: "updated_at"},
 		},
-		PKFieldIndex: 0,
+		PKFieldIndex:   0,
+		PKFieldIndexes: []int{0},
 	},
 	z: new(Person).Values(),
 }
@@ -130,6 +136,22 @@ func (s *Person) PKPointer() interface{} {
 	return &s.ID
 }
 
+// PKValues returns a slice of primary key field values for that record.
+// Returned interface{} values are never untyped nils.
+func (s *Person) PKValues() []interface{} {
+	return []interface{}{
+		s.ID,
+	}
+}
+
+// PKPointers returns a slice of pointers to primary key fields for that record.
+// Returned interface{} values are never untyped nils.
+func (s *Person) PKPointers() []interface{} {
+	return []interface{}{
+		&s.ID,
+	}
+}
+
 // HasPK returns true if record has non-zero primary key set, false otherwise.
 func (s *Person) HasPK() bool {
 	return s.ID != PersonTable.z[PersonTable.s.PKFieldIndex]
@@ -191,6 +213,11 @@ func (v *projectTableType) PKColumnIndex() uint {
 	return uint(v.s.PKFieldIndex)
 }
 
+// PKColumnIndexes returns a new slice of indexes of all primary key columns for that table in SQL database.
+func (v *projectTableType) PKColumnIndexes() []uint {
+	return []uint{1}
+}
+
 // ProjectTable represents projects view or table in SQL database.
 var ProjectTable = &projectTableType{
 	s: parse.StructInfo{
@@ -202,7 +229,8 @@ var ProjectTable = &projectTableType{
 			{Name: "Start", Type: "time.Time", Column: "start"},
 			{Name: "End", Type: "*time.Time", Column: "end"},
 		},
-		PKFieldIndex: 1,
+		PKFieldIndex:   1,
+		PKFieldIndexes: []int{1},
 	},
 	z: new(Project).Values(),
 }
@@ -261,6 +289,22 @@ func (s *Project) PKPointer() interface{} {
 	return &s.ID
 }
 
+// PKValues returns a slice of primary key field values for that record.
+// Returned interface{} values are never untyped nils.
+func (s *Project) PKValues() []interface{} {
+	return []interface{}{
+		s.ID,
+	}
+}
+
+// PKPointers returns a slice of pointers to primary key fields for that record.
+// Returned interface{} values are never untyped nils.
+func (s *Project) PKPointers() []interface{} {
+	return []interface{}{
+		&s.ID,
+	}
+}
+
 // HasPK returns true if record has non-zero primary key set, false otherwise.
 func (s *Project) HasPK() bool {
 	return s.ID != ProjectTable.z[ProjectTable.s.PKFieldIndex]
@@ -310,14 +354,22 @@ func (v *personProjectViewType) NewStruct() reform.Struct {
 	return new(PersonProject)
 }
 
+// ForeignKeys returns a new slice of foreign keys for that view or table.
+func (v *personProjectViewType) ForeignKeys() []reform.ForeignKey {
+	return []reform.ForeignKey{
+		{Column: "person_id", RefTable: "people", RefColumn: "id"},
+		{Column: "project_id", RefTable: "projects", RefColumn: "id"},
+	}
+}
+
 // PersonProjectView represents person_project view or table in SQL database.
 var PersonProjectView = &personProjectViewType{
 	s: parse.StructInfo{
 		Type:    "PersonProject",
 		SQLName: "person_project",
 		Fields: []parse.FieldInfo{
-			{Name: "PersonID", Type: "int32", Column: "person_id"},
-			{Name: "ProjectID", Type: "string", Column: "project_id"},
+			{Name: "PersonID", Type: "int32", Column: "person_id", FK: "people.id"},
+			{Name: "ProjectID", Type: "string", Column: "project_id", FK: "projects.id"},
 		},
 		PKFieldIndex: -1,
 	},
@@ -357,9 +409,10 @@ func (s *PersonProject) View() reform.View {
 
 // check interfaces
 var (
-	_ reform.View   = PersonProjectView
-	_ reform.Struct = (*PersonProject)(nil)
-	_ fmt.Stringer  = (*PersonProject)(nil)
+	_ reform.View          = PersonProjectView
+	_ reform.RelationsView = PersonProjectView
+	_ reform.Struct        = (*PersonProject)(nil)
+	_ fmt.Stringer         = (*PersonProject)(nil)
 )
 
 type iDOnlyTableType struct {
@@ -399,6 +452,11 @@ func (v *iDOnlyTableType) PKColumnIndex() uint {
 	return uint(v.s.PKFieldIndex)
 }
 
+// PKColumnIndexes returns a new slice of indexes of all primary key columns for that table in SQL database.
+func (v *iDOnlyTableType) PKColumnIndexes() []uint {
+	return []uint{0}
+}
+
 // IDOnlyTable represents id_only view or table in SQL database.
 var IDOnlyTable = &iDOnlyTableType{
 	s: parse.StructInfo{
@@ -407,7 +465,8 @@ var IDOnlyTable = &iDOnlyTableType{
 		Fields: []parse.FieldInfo{
 			{Name: "ID", Type: "int32", Column: "id"},
 		},
-		PKFieldIndex: 0,
+		PKFieldIndex:   0,
+		PKFieldIndexes: []int{0},
 	},
 	z: new(IDOnly).Values(),
 }
@@ -457,6 +516,22 @@ func (s *IDOnly) PKPointer() interface{} {
 	return &s.ID
 }
 
+// PKValues returns a slice of primary key field values for that record.
+// Returned interface{} values are never untyped nils.
+func (s *IDOnly) PKValues() []interface{} {
+	return []interface{}{
+		s.ID,
+	}
+}
+
+// PKPointers returns a slice of pointers to primary key fields for that record.
+// Returned interface{} values are never untyped nils.
+func (s *IDOnly) PKPointers() []interface{} {
+	return []interface{}{
+		&s.ID,
+	}
+}
+
 // HasPK returns true if record has non-zero primary key set, false otherwise.
 func (s *IDOnly) HasPK() bool {
 	return s.ID != IDOnlyTable.z[IDOnlyTable.s.PKFieldIndex]
@@ -516,6 +591,11 @@ func (v *constraintsTableType) PKColumnIndex() uint {
 	return uint(v.s.PKFieldIndex)
 }
 
+// PKColumnIndexes returns a new slice of indexes of all primary key columns for that table in SQL database.
+func (v *constraintsTableType) PKColumnIndexes() []uint {
+	return []uint{1}
+}
+
 // ConstraintsTable represents constraints view or table in SQL database.
 var ConstraintsTable = &constraintsTableType{
 	s: parse.StructInfo{
@@ -525,7 +605,8 @@ var ConstraintsTable = &constraintsTableType{
 			{Name: "I", Type: "int32", Column: "i"},
 			{Name: "ID", Type: "string", Column: "id"},
 		},
-		PKFieldIndex: 1,
+		PKFieldIndex:   1,
+		PKFieldIndexes: []int{1},
 	},
 	z: new(Constraints).Values(),
 }
@@ -578,6 +659,22 @@ func (s *Constraints) PKPointer() interface{} {
 	return &s.ID
 }
 
+// PKValues returns a slice of primary key field values for that record.
+// Returned interface{} values are never untyped nils.
+func (s *Constraints) PKValues() []interface{} {
+	return []interface{}{
+		s.ID,
+	}
+}
+
+// PKPointers returns a slice of pointers to primary key fields for that record.
+// Returned interface{} values are never untyped nils.
+func (s *Constraints) PKPointers() []interface{} {
+	return []interface{}{
+		&s.ID,
+	}
+}
+
 // HasPK returns true if record has non-zero primary key set, false otherwise.
 func (s *Constraints) HasPK() bool {
 	return s.ID != ConstraintsTable.z[ConstraintsTable.s.PKFieldIndex]
@@ -684,6 +781,341 @@ var (
 	_ fmt.Stringer  = (*CompositePk)(nil)
 )
 
+type compositePkRecordTableType struct {
+	s parse.StructInfo
+	z []interface{}
+}
+
+// Schema returns a schema name in SQL database ("").
+func (v *compositePkRecordTableType) Schema() string {
+	return v.s.SQLSchema
+}
+
+// Name returns a view or table name in SQL database ("composite_pk").
+func (v *compositePkRecordTableType) Name() string {
+	return v.s.SQLName
+}
+
+// Columns returns a new slice of column names for that view or table in SQL database.
+func (v *compositePkRecordTableType) Columns() []string {
+	return []string{
+		"i",
+		"name",
+		"j",
+	}
+}
+
+// NewStruct makes a new struct for that view or table.
+func (v *compositePkRecordTableType) NewStruct() reform.Struct {
+	return new(CompositePkRecord)
+}
+
+// NewRecord makes a new record for that table.
+func (v *compositePkRecordTableType) NewRecord() reform.Record {
+	return new(CompositePkRecord)
+}
+
+// PKColumnIndex returns an index of primary key column for that table in SQL database.
+func (v *compositePkRecordTableType) PKColumnIndex() uint {
+	return uint(v.s.PKFieldIndex)
+}
+
+// PKColumnIndexes returns a new slice of indexes of all primary key columns for that table in SQL database.
+func (v *compositePkRecordTableType) PKColumnIndexes() []uint {
+	return []uint{0, 2}
+}
+
+// CompositePkRecordTable represents composite_pk view or table in SQL database.
+var CompositePkRecordTable = &compositePkRecordTableType{
+	s: parse.StructInfo{
+		Type:    "CompositePkRecord",
+		SQLName: "composite_pk",
+		Fields: []parse.FieldInfo{
+			{Name: "I", Type: "int32", Column: "i"},
+			{Name: "Name", Type: "string", Column: "name"},
+			{Name: "J", Type: "string", Column: "j"},
+		},
+		PKFieldIndex:   0,
+		PKFieldIndexes: []int{0, 2},
+	},
+	z: new(CompositePkRecord).Values(),
+}
+
+// String returns a string representation of this struct or record.
+func (s CompositePkRecord) String() string {
+	res := make([]string, 3)
+	res[0] = "I: " + reform.Inspect(s.I, true)
+	res[1] = "Name: " + reform.Inspect(s.Name, true)
+	res[2] = "J: " + reform.Inspect(s.J, true)
+	return strings.Join(res, ", ")
+}
+
+// Values returns a slice of struct or record field values.
+// Returned interface{} values are never untyped nils.
+func (s *CompositePkRecord) Values() []interface{} {
+	return []interface{}{
+		s.I,
+		s.Name,
+		s.J,
+	}
+}
+
+// Pointers returns a slice of pointers to struct or record fields.
+// Returned interface{} values are never untyped nils.
+func (s *CompositePkRecord) Pointers() []interface{} {
+	return []interface{}{
+		&s.I,
+		&s.Name,
+		&s.J,
+	}
+}
+
+// View returns View object for that struct.
+func (s *CompositePkRecord) View() reform.View {
+	return CompositePkRecordTable
+}
+
+// Table returns Table object for that record.
+func (s *CompositePkRecord) Table() reform.Table {
+	return CompositePkRecordTable
+}
+
+// PKValue panics as that record has composite primary key. Use PKValues instead.
+func (s *CompositePkRecord) PKValue() interface{} {
+	panic("reform: CompositePkRecord has composite primary key, use PKValues")
+}
+
+// PKPointer panics as that record has composite primary key. Use PKPointers instead.
+func (s *CompositePkRecord) PKPointer() interface{} {
+	panic("reform: CompositePkRecord has composite primary key, use PKPointers")
+}
+
+// PKValues returns a slice of primary key field values for that record.
+// Returned interface{} values are never untyped nils.
+func (s *CompositePkRecord) PKValues() []interface{} {
+	return []interface{}{
+		s.I,
+		s.J,
+	}
+}
+
+// PKPointers returns a slice of pointers to primary key fields for that record.
+// Returned interface{} values are never untyped nils.
+func (s *CompositePkRecord) PKPointers() []interface{} {
+	return []interface{}{
+		&s.I,
+		&s.J,
+	}
+}
+
+// HasPK returns true if record has non-zero primary key set, false otherwise.
+func (s *CompositePkRecord) HasPK() bool {
+	return s.I != CompositePkRecordTable.z[0] &&
+		s.J != CompositePkRecordTable.z[2]
+}
+
+// SetPK panics as that record has composite primary key. Use PKPointers instead.
+//
+// Deprecated: prefer direct field assignment where possible.
+func (s *CompositePkRecord) SetPK(pk interface{}) {
+	panic("reform: CompositePkRecord has composite primary key, use PKPointers")
+}
+
+// check interfaces
+var (
+	_ reform.View   = CompositePkRecordTable
+	_ reform.Struct = (*CompositePkRecord)(nil)
+	_ reform.Table  = CompositePkRecordTable
+	_ reform.Record = (*CompositePkRecord)(nil)
+	_ fmt.Stringer  = (*CompositePkRecord)(nil)
+)
+
+type documentTableType struct {
+	s parse.StructInfo
+	z []interface{}
+}
+
+// Schema returns a schema name in SQL database ("").
+func (v *documentTableType) Schema() string {
+	return v.s.SQLSchema
+}
+
+// Name returns a view or table name in SQL database ("documents").
+func (v *documentTableType) Name() string {
+	return v.s.SQLName
+}
+
+// Columns returns a new slice of column names for that view or table in SQL database.
+func (v *documentTableType) Columns() []string {
+	return []string{
+		"id",
+		"title",
+		"version",
+		"created_at",
+		"updated_at",
+		"deleted_at",
+	}
+}
+
+// NewStruct makes a new struct for that view or table.
+func (v *documentTableType) NewStruct() reform.Struct {
+	return new(Document)
+}
+
+// AutoCreateColumnIndexes returns a new slice of indexes of columns set on insert.
+func (v *documentTableType) AutoCreateColumnIndexes() []uint {
+	return []uint{3}
+}
+
+// AutoUpdateColumnIndexes returns a new slice of indexes of columns set on update.
+func (v *documentTableType) AutoUpdateColumnIndexes() []uint {
+	return []uint{4}
+}
+
+// SoftDeleteColumnIndex returns an index of soft delete column for that view or table in SQL database.
+func (v *documentTableType) SoftDeleteColumnIndex() uint {
+	return 5
+}
+
+// NewRecord makes a new record for that table.
+func (v *documentTableType) NewRecord() reform.Record {
+	return new(Document)
+}
+
+// PKColumnIndex returns an index of primary key column for that table in SQL database.
+func (v *documentTableType) PKColumnIndex() uint {
+	return uint(v.s.PKFieldIndex)
+}
+
+// PKColumnIndexes returns a new slice of indexes of all primary key columns for that table in SQL database.
+func (v *documentTableType) PKColumnIndexes() []uint {
+	return []uint{0}
+}
+
+// VersionColumnIndex returns an index of version column for that table in SQL database.
+func (v *documentTableType) VersionColumnIndex() uint {
+	return 2
+}
+
+// DocumentTable represents documents view or table in SQL database.
+var DocumentTable = &documentTableType{
+	s: parse.StructInfo{
+		Type:    "Document",
+		SQLName: "documents",
+		Fields: []parse.FieldInfo{
+			{Name: "ID", Type: "int32", Column: "id"},
+			{Name: "Title", Type: "string", Column: "title"},
+			{Name: "Version", Type: "int32", Column: "version", Version: true},
+			{Name: "CreatedAt", Type: "time.Time", Column: "created_at", AutoCreate: true},
+			{Name: "UpdatedAt", Type: "*time.Time", Column: "updated_at", AutoUpdate: true},
+			{Name: "DeletedAt", Type: "*time.Time", Column: "deleted_at", SoftDelete: true},
+		},
+		PKFieldIndex:   0,
+		PKFieldIndexes: []int{0},
+	},
+	z: new(Document).Values(),
+}
+
+// String returns a string representation of this struct or record.
+func (s Document) String() string {
+	res := make([]string, 6)
+	res[0] = "ID: " + reform.Inspect(s.ID, true)
+	res[1] = "Title: " + reform.Inspect(s.Title, true)
+	res[2] = "Version: " + reform.Inspect(s.Version, true)
+	res[3] = "CreatedAt: " + reform.Inspect(s.CreatedAt, true)
+	res[4] = "UpdatedAt: " + reform.Inspect(s.UpdatedAt, true)
+	res[5] = "DeletedAt: " + reform.Inspect(s.DeletedAt, true)
+	return strings.Join(res, ", ")
+}
+
+// Values returns a slice of struct or record field values.
+// Returned interface{} values are never untyped nils.
+func (s *Document) Values() []interface{} {
+	return []interface{}{
+		s.ID,
+		s.Title,
+		s.Version,
+		s.CreatedAt,
+		s.UpdatedAt,
+		s.DeletedAt,
+	}
+}
+
+// Pointers returns a slice of pointers to struct or record fields.
+// Returned interface{} values are never untyped nils.
+func (s *Document) Pointers() []interface{} {
+	return []interface{}{
+		&s.ID,
+		&s.Title,
+		&s.Version,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+		&s.DeletedAt,
+	}
+}
+
+// View returns View object for that struct.
+func (s *Document) View() reform.View {
+	return DocumentTable
+}
+
+// Table returns Table object for that record.
+func (s *Document) Table() reform.Table {
+	return DocumentTable
+}
+
+// PKValue returns a value of primary key for that record.
+// Returned interface{} value is never untyped nil.
+func (s *Document) PKValue() interface{} {
+	return s.ID
+}
+
+// PKPointer returns a pointer to primary key field for that record.
+// Returned interface{} value is never untyped nil.
+func (s *Document) PKPointer() interface{} {
+	return &s.ID
+}
+
+// PKValues returns a slice of primary key field values for that record.
+// Returned interface{} values are never untyped nils.
+func (s *Document) PKValues() []interface{} {
+	return []interface{}{
+		s.ID,
+	}
+}
+
+// PKPointers returns a slice of pointers to primary key fields for that record.
+// Returned interface{} values are never untyped nils.
+func (s *Document) PKPointers() []interface{} {
+	return []interface{}{
+		&s.ID,
+	}
+}
+
+// HasPK returns true if record has non-zero primary key set, false otherwise.
+func (s *Document) HasPK() bool {
+	return s.ID != DocumentTable.z[DocumentTable.s.PKFieldIndex]
+}
+
+// SetPK sets record primary key, if possible.
+//
+// Deprecated: prefer direct field assignment where possible: s.ID = pk.
+func (s *Document) SetPK(pk interface{}) {
+	reform.SetPK(s, pk)
+}
+
+// check interfaces
+var (
+	_ reform.View           = DocumentTable
+	_ reform.SoftDeleteView = DocumentTable
+	_ reform.AutoTimeView   = DocumentTable
+	_ reform.Struct         = (*Document)(nil)
+	_ reform.Table          = DocumentTable
+	_ reform.VersionedTable = DocumentTable
+	_ reform.Record         = (*Document)(nil)
+	_ fmt.Stringer          = (*Document)(nil)
+)
+
 type legacyPersonTableType struct {
 	s parse.StructInfo
 	z []interface{}
@@ -722,6 +1154,11 @@ func (v *legacyPersonTableType) PKColumnIndex() uint {
 	return uint(v.s.PKFieldIndex)
 }
 
+// PKColumnIndexes returns a new slice of indexes of all primary key columns for that table in SQL database.
+func (v *legacyPersonTableType) PKColumnIndexes() []uint {
+	return []uint{0}
+}
+
 // LegacyPersonTable represents people view or table in SQL database.
 var LegacyPersonTable = &legacyPersonTableType{
 	s: parse.StructInfo{
@@ -732,7 +1169,8 @@ var LegacyPersonTable = &legacyPersonTableType{
 			{Name: "ID", Type: "int32", Column: "id"},
 			{Name: "Name", Type: "*string", Column: "name"},
 		},
-		PKFieldIndex: 0,
+		PKFieldIndex:   0,
+		PKFieldIndexes: []int{0},
 	},
 	z: new(LegacyPerson).Values(),
 }
@@ -785,6 +1223,22 @@ func (s *LegacyPerson) PKPointer() interface{} {
 	return &s.ID
 }
 
+// PKValues returns a slice of primary key field values for that record.
+// Returned interface{} values are never untyped nils.
+func (s *LegacyPerson) PKValues() []interface{} {
+	return []interface{}{
+		s.ID,
+	}
+}
+
+// PKPointers returns a slice of pointers to primary key fields for that record.
+// Returned interface{} values are never untyped nils.
+func (s *LegacyPerson) PKPointers() []interface{} {
+	return []interface{}{
+		&s.ID,
+	}
+}
+
 // HasPK returns true if record has non-zero primary key set, false otherwise.
 func (s *LegacyPerson) HasPK() bool {
 	return s.ID != LegacyPersonTable.z[LegacyPersonTable.s.PKFieldIndex]
@@ -813,5 +1267,7 @@ func init() {
 	parse.AssertUpToDate(&IDOnlyTable.s, new(IDOnly))
 	parse.AssertUpToDate(&ConstraintsTable.s, new(Constraints))
 	parse.AssertUpToDate(&CompositePkView.s, new(CompositePk))
+	parse.AssertUpToDate(&CompositePkRecordTable.s, new(CompositePkRecord))
+	parse.AssertUpToDate(&DocumentTable.s, new(Document))
 	parse.AssertUpToDate(&LegacyPersonTable.s, new(LegacyPerson))
 }
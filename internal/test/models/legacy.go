@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+//go:generate reform
+
+//reform:legacy.documents
+type LegacyDocument struct {
+	ID        int32      `reform:"id,pk"`
+	Title     string     `reform:"title"`
+	DeletedAt *time.Time `reform:"deleted_at,softdelete"`
+}
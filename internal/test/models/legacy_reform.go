@@ -0,0 +1,170 @@
+// Code generated by gopkg.in/reform.v1. DO NOT EDIT.
+
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/reform.v1"
+	"gopkg.in/reform.v1/parse"
+)
+
+type legacyDocumentTableType struct {
+	s parse.StructInfo
+	z []interface{}
+}
+
+// Schema returns a schema name in SQL database ("legacy").
+func (v *legacyDocumentTableType) Schema() string {
+	return v.s.SQLSchema
+}
+
+// Name returns a view or table name in SQL database ("documents").
+func (v *legacyDocumentTableType) Name() string {
+	return v.s.SQLName
+}
+
+// Columns returns a new slice of column names for that view or table in SQL database.
+func (v *legacyDocumentTableType) Columns() []string {
+	return []string{
+		"id",
+		"title",
+		"deleted_at",
+	}
+}
+
+// NewStruct makes a new struct for that view or table.
+func (v *legacyDocumentTableType) NewStruct() reform.Struct {
+	return new(LegacyDocument)
+}
+
+// SoftDeleteColumnIndex returns an index of soft delete column for that view or table in SQL database.
+func (v *legacyDocumentTableType) SoftDeleteColumnIndex() uint {
+	return 2
+}
+
+// NewRecord makes a new record for that table.
+func (v *legacyDocumentTableType) NewRecord() reform.Record {
+	return new(LegacyDocument)
+}
+
+// PKColumnIndex returns an index of primary key column for that table in SQL database.
+func (v *legacyDocumentTableType) PKColumnIndex() uint {
+	return uint(v.s.PKFieldIndex)
+}
+
+// PKColumnIndexes returns a new slice of indexes of all primary key columns for that table in SQL database.
+func (v *legacyDocumentTableType) PKColumnIndexes() []uint {
+	return []uint{0}
+}
+
+// LegacyDocumentTable represents documents view or table in SQL database.
+var LegacyDocumentTable = &legacyDocumentTableType{
+	s: parse.StructInfo{
+		Type:      "LegacyDocument",
+		SQLSchema: "legacy",
+		SQLName:   "documents",
+		Fields: []parse.FieldInfo{
+			{Name: "ID", Type: "int32", Column: "id"},
+			{Name: "Title", Type: "string", Column: "title"},
+			{Name: "DeletedAt", Type: "*time.Time", Column: "deleted_at", SoftDelete: true},
+		},
+		PKFieldIndex:   0,
+		PKFieldIndexes: []int{0},
+	},
+	z: new(LegacyDocument).Values(),
+}
+
+// String returns a string representation of this struct or record.
+func (s LegacyDocument) String() string {
+	res := make([]string, 3)
+	res[0] = "ID: " + reform.Inspect(s.ID, true)
+	res[1] = "Title: " + reform.Inspect(s.Title, true)
+	res[2] = "DeletedAt: " + reform.Inspect(s.DeletedAt, true)
+	return strings.Join(res, ", ")
+}
+
+// Values returns a slice of struct or record field values.
+// Returned interface{} values are never untyped nils.
+func (s *LegacyDocument) Values() []interface{} {
+	return []interface{}{
+		s.ID,
+		s.Title,
+		s.DeletedAt,
+	}
+}
+
+// Pointers returns a slice of pointers to struct or record fields.
+// Returned interface{} values are never untyped nils.
+func (s *LegacyDocument) Pointers() []interface{} {
+	return []interface{}{
+		&s.ID,
+		&s.Title,
+		&s.DeletedAt,
+	}
+}
+
+// View returns View object for that struct.
+func (s *LegacyDocument) View() reform.View {
+	return LegacyDocumentTable
+}
+
+// Table returns Table object for that record.
+func (s *LegacyDocument) Table() reform.Table {
+	return LegacyDocumentTable
+}
+
+// PKValue returns a value of primary key for that record.
+// Returned interface{} value is never untyped nil.
+func (s *LegacyDocument) PKValue() interface{} {
+	return s.ID
+}
+
+// PKPointer returns a pointer to primary key field for that record.
+// Returned interface{} value is never untyped nil.
+func (s *LegacyDocument) PKPointer() interface{} {
+	return &s.ID
+}
+
+// PKValues returns a slice of primary key field values for that record.
+// Returned interface{} values are never untyped nils.
+func (s *LegacyDocument) PKValues() []interface{} {
+	return []interface{}{
+		s.ID,
+	}
+}
+
+// PKPointers returns a slice of pointers to primary key fields for that record.
+// Returned interface{} values are never untyped nils.
+func (s *LegacyDocument) PKPointers() []interface{} {
+	return []interface{}{
+		&s.ID,
+	}
+}
+
+// HasPK returns true if record has non-zero primary key set, false otherwise.
+func (s *LegacyDocument) HasPK() bool {
+	return s.ID != LegacyDocumentTable.z[LegacyDocumentTable.s.PKFieldIndex]
+}
+
+// SetPK sets record primary key, if possible.
+//
+// Deprecated: prefer direct field assignment where possible: s.ID = pk.
+func (s *LegacyDocument) SetPK(pk interface{}) {
+	reform.SetPK(s, pk)
+}
+
+// check interfaces
+var (
+	_ reform.View           = LegacyDocumentTable
+	_ reform.SoftDeleteView = LegacyDocumentTable
+	_ reform.Struct         = (*LegacyDocument)(nil)
+	_ reform.Table          = LegacyDocumentTable
+	_ reform.Record         = (*LegacyDocument)(nil)
+	_ fmt.Stringer          = (*LegacyDocument)(nil)
+)
+
+func init() {
+	parse.AssertUpToDate(&LegacyDocumentTable.s, new(LegacyDocument))
+}
@@ -0,0 +1,124 @@
+package reform
+
+import (
+	"database/sql"
+)
+
+// Iterator iterates over result rows of SELECT query for a single view without loading all of them into memory.
+// It is created by Querier.Iterate. Rows are closed automatically when iteration finishes or fails;
+// call Close to stop iteration early.
+//
+// Idiomatic usage:
+//
+//	iter, err := q.Iterate(PersonTable, "ORDER BY id")
+//	if err != nil {
+//		return err
+//	}
+//	defer iter.Close()
+//	for iter.Next() {
+//		person := iter.Struct().(*Person)
+//		...
+//	}
+//	if err = iter.Err(); err != nil {
+//		return err
+//	}
+type Iterator struct {
+	// AllocatePerRow makes Next allocate a new Struct for each row.
+	// By default, a single Struct is reused, so it should not be retained between Next calls.
+	AllocatePerRow bool
+
+	q    *Querier
+	view View
+	rows *sql.Rows
+	str  Struct
+	err  error
+}
+
+// Iterate queries view with tail and args and returns Iterator over result rows.
+//
+// In case of error iterator will be nil. Error is never ErrNoRows.
+func (q *Querier) Iterate(view View, tail string, args ...interface{}) (*Iterator, error) {
+	rows, err := q.SelectRows(view, tail, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Iterator{
+		q:    q,
+		view: view,
+		rows: rows,
+	}, nil
+}
+
+// Next scans next result row to Struct. If Struct implements AfterFinder, it also calls AfterFind().
+// It returns false if there are no more rows or error is encountered; rows are closed in both cases.
+func (it *Iterator) Next() bool {
+	if it.rows == nil {
+		return false
+	}
+
+	if it.str == nil || it.AllocatePerRow {
+		it.str = it.view.NewStruct()
+	}
+
+	err := it.q.NextRow(it.str, it.rows)
+	if err == nil {
+		return true
+	}
+
+	if err != ErrNoRows {
+		it.err = err
+	}
+	it.str = nil
+	_ = it.Close()
+	return false
+}
+
+// Struct returns Struct scanned by the last Next call.
+func (it *Iterator) Struct() Struct {
+	return it.str
+}
+
+// Err returns error encountered during iteration or closing rows, if any. It is never ErrNoRows.
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+// Close closes rows. It is safe to call it several times and after iteration is finished.
+// It returns the same error as Err.
+func (it *Iterator) Close() error {
+	if it.rows == nil {
+		return it.err
+	}
+
+	if err := it.rows.Close(); err != nil && it.err == nil {
+		it.err = err
+	}
+	it.rows = nil
+	return it.err
+}
+
+// ForEach queries view with tail and args and calls f for each result row.
+// If view's Struct implements AfterFinder, it also calls AfterFind() before f.
+// A single Struct is reused for all rows, so f should not retain it.
+//
+// Iteration stops on the first error returned by f, and that error is returned.
+// Rows are always closed. Error is never ErrNoRows.
+func (q *Querier) ForEach(view View, tail string, args []interface{}, f func(Struct) error) (err error) {
+	var iter *Iterator
+	if iter, err = q.Iterate(view, tail, args...); err != nil {
+		return
+	}
+	defer func() {
+		if e := iter.Close(); err == nil {
+			err = e
+		}
+	}()
+
+	for iter.Next() {
+		if err = f(iter.Struct()); err != nil {
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,144 @@
+package reform
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// joinAliases returns unquoted aliases for views in SELECT query with joins:
+// view's name for the first occurrence of the view, and name_N for N-th occurrence (for self-joins).
+func joinAliases(views []View) []string {
+	res := make([]string, len(views))
+	seen := make(map[string]int, len(views))
+	for i, view := range views {
+		name := view.Name()
+		seen[name]++
+		if n := seen[name]; n > 1 {
+			res[i] = fmt.Sprintf("%s_%d", name, n)
+		} else {
+			res[i] = name
+		}
+	}
+	return res
+}
+
+// selectJoinQuery returns full SELECT query for given views and join tail.
+func (q *Querier) selectJoinQuery(views []View, joinTail string) string {
+	aliases := joinAliases(views)
+	var columns []string
+	for i, view := range views {
+		alias := q.QuoteIdentifier(aliases[i])
+		for _, c := range view.Columns() {
+			columns = append(columns, alias+"."+q.QuoteIdentifier(c))
+		}
+	}
+
+	// soft-deleted rows of the first view are excluded by derived table with the same alias
+	from, _ := q.selectFrom(views[0])
+	if from == q.QualifiedView(views[0]) {
+		from += " AS " + q.QuoteIdentifier(aliases[0])
+	}
+
+	return fmt.Sprintf("%s %s FROM %s %s",
+		q.startQuery("SELECT"), strings.Join(columns, ", "), from, joinTail)
+}
+
+// SelectJoinRows queries the first of views joined with others by joinTail and args, and returns rows.
+// They can then be iterated with NextJoinRow().
+// It is caller's responsibility to call rows.Close().
+//
+// Columns of all views are selected. Each view is aliased with its name; if the same view is used several times
+// (self-join), N-th occurrence is aliased with name_N (for example, people_2). joinTail should contain
+// JOIN clauses for all views except the first one using those aliases, and may contain WHERE, ORDER BY and other clauses:
+//
+//	JOIN people AS people_2 ON people.id = people_2.manager_id WHERE people.id = $1
+//
+// Soft-deleted rows are excluded only for the first view.
+//
+// In case of error rows will be nil. Error is never ErrNoRows.
+func (q *Querier) SelectJoinRows(views []View, joinTail string, args ...interface{}) (*sql.Rows, error) {
+	if len(views) == 0 {
+		return nil, fmt.Errorf("reform: no views in SelectJoin")
+	}
+
+	joinTail, args, err := q.bindNamed(joinTail, 1, args)
+	if err != nil {
+		return nil, err
+	}
+
+	query := q.selectJoinQuery(views, joinTail)
+	return q.read(views[0], query, args...)
+}
+
+// NextJoinRow scans next result row from rows returned by SelectJoinRows to structs, one struct per view.
+// If structs implement AfterFinder, it also calls AfterFind() on each of them.
+// It is caller's responsibility to call rows.Close().
+//
+// Use pointer fields for views joined with outer joins, as their columns may be NULL.
+//
+// If there is no next result row, it returns ErrNoRows. It also may return rows.Err(), rows.Scan()
+// and AfterFinder errors.
+func (q *Querier) NextJoinRow(structs []Struct, rows *sql.Rows) error {
+	var err error
+	next := rows.Next()
+	if !next {
+		err = rows.Err()
+		if err == nil {
+			err = ErrNoRows
+		}
+		return err
+	}
+
+	var pointers []interface{}
+	for _, str := range structs {
+		pointers = append(pointers, str.Pointers()...)
+	}
+	if err = rows.Scan(pointers...); err != nil {
+		return err
+	}
+
+	for _, str := range structs {
+		if err = q.callAfterFind(str); err != nil {
+			return err
+		}
+		q.snapshot(str, nil)
+	}
+	return nil
+}
+
+// SelectJoin queries the first of views joined with others by joinTail and args (see SelectJoinRows),
+// and returns a slice of rows, each with new Structs, one per view.
+// If views' Structs implement AfterFinder, it also calls AfterFind() on each of them.
+//
+// In case of query error slice will be nil. If error is encountered during iteration,
+// partial result and error will be returned. Error is never ErrNoRows.
+func (q *Querier) SelectJoin(views []View, joinTail string, args ...interface{}) (res [][]Struct, err error) {
+	var rows *sql.Rows
+	rows, err = q.SelectJoinRows(views, joinTail, args...)
+	if err != nil {
+		return
+	}
+	defer func() {
+		e := rows.Close()
+		if err == nil {
+			err = e
+		}
+	}()
+
+	for {
+		structs := make([]Struct, len(views))
+		for i, view := range views {
+			structs[i] = view.NewStruct()
+		}
+		if err = q.NextJoinRow(structs, rows); err != nil {
+			break
+		}
+
+		res = append(res, structs)
+	}
+	if err == ErrNoRows {
+		err = nil
+	}
+	return
+}
@@ -0,0 +1,148 @@
+package reform
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// NamedArgs binds named parameters in query tails. It is created by Named.
+type NamedArgs struct {
+	values map[string]interface{}
+	strict bool // return error for unused values
+}
+
+// Named returns NamedArgs for named parameters ":name" or "@name" in tail. It should be the only argument
+// of Querier's methods with tail: SelectOneTo, SelectOneFrom, SelectRows, SelectAllFrom, SelectJoin, SelectJoinRows,
+// Count, Exists, Sum, Min, Max, Avg, CountDistinct, UpdateView and DeleteFrom.
+// They replace named parameters with Dialect's placeholders:
+//
+//	people, err := q.SelectAllFrom(PersonTable, "WHERE name = :name OR email = :email",
+//	    reform.Named(map[string]interface{}{"name": "Alice", "email": "alice@example.com"}))
+//
+// Values may be given by map[string]interface{}, by Struct (parameter names are column names),
+// or by other struct or pointer to struct (parameter names are the first parts of "reform:" tags, or field names
+// for fields without them). It is an error if tail contains a parameter without value,
+// or if map contains a value that is not used in tail.
+//
+// Named parameters are not recognized inside quoted strings and identifiers, comments and dollar-quoted strings;
+// "::" (PostgreSQL cast) and "@@" (SQL Server system function) are left as is.
+func Named(v interface{}) NamedArgs {
+	if m, ok := v.(map[string]interface{}); ok {
+		return NamedArgs{values: m, strict: true}
+	}
+
+	if str, ok := v.(Struct); ok {
+		columns := str.View().Columns()
+		values := str.Values()
+		res := NamedArgs{values: make(map[string]interface{}, len(columns))}
+		for i, c := range columns {
+			res.values[c] = values[i]
+		}
+		return res
+	}
+
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("reform: Named expects map[string]interface{} or struct, got %T", v))
+	}
+	t := rv.Type()
+	res := NamedArgs{values: make(map[string]interface{}, t.NumField())}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		name := f.Name
+		if tag := strings.Split(f.Tag.Get("reform"), ",")[0]; tag != "" {
+			if tag == "-" {
+				continue
+			}
+			name = tag
+		}
+		res.values[name] = rv.Field(i).Interface()
+	}
+	return res
+}
+
+// bindNamed replaces named parameters in tail with Dialect's placeholders starting from given index
+// if args consists of a single NamedArgs, and returns new tail and arguments.
+// Otherwise, it returns tail and args as is.
+func (q *Querier) bindNamed(tail string, start int, args []interface{}) (string, []interface{}, error) {
+	var named *NamedArgs
+	for _, arg := range args {
+		if n, ok := arg.(NamedArgs); ok {
+			named = &n
+			break
+		}
+	}
+	if named == nil {
+		return tail, args, nil
+	}
+	if len(args) != 1 {
+		return "", nil, fmt.Errorf("reform: Named should be the only argument")
+	}
+
+	backslash, dollar := q.stringSyntax()
+
+	var res strings.Builder
+	var resArgs []interface{}
+	used := make(map[string]bool, len(named.values))
+	for i := 0; i < len(tail); i++ {
+		// copy quoted strings and identifiers, comments and dollar-quoted strings as is
+		if end := skippedEnd(tail, i, backslash, dollar); end >= 0 {
+			res.WriteString(tail[i : end+1])
+			i = end
+			continue
+		}
+
+		c := tail[i]
+		switch {
+		case (c == ':' || c == '@') && i+1 < len(tail) && tail[i+1] == c:
+			// "::" or "@@"
+			res.WriteString(tail[i : i+2])
+			i++
+
+		case (c == ':' || c == '@') && i+1 < len(tail) && isNameStart(tail[i+1]):
+			end := i + 2
+			for end < len(tail) && isNamePart(tail[end]) {
+				end++
+			}
+			name := tail[i+1 : end]
+			v, ok := named.values[name]
+			if !ok {
+				return "", nil, fmt.Errorf("reform: missing value for named parameter %s", name)
+			}
+			used[name] = true
+			resArgs = append(resArgs, v)
+			res.WriteString(q.Placeholder(start + len(resArgs) - 1))
+			i = end - 1
+
+		default:
+			res.WriteByte(c)
+		}
+	}
+
+	if named.strict && len(used) != len(named.values) {
+		var unused []string
+		for name := range named.values {
+			if !used[name] {
+				unused = append(unused, name)
+			}
+		}
+		sort.Strings(unused)
+		return "", nil, fmt.Errorf("reform: unused named parameters: %v", unused)
+	}
+
+	return res.String(), resArgs, nil
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isNamePart(c byte) bool {
+	return isNameStart(c) || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,190 @@
+package reform
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// pageCursor is a decoded cursor for SelectPage.
+type pageCursor struct {
+	Columns []string          `json:"c"`
+	Values  []json.RawMessage `json:"v"`
+}
+
+// pageColumns returns indexes of given columns in view, or of primary key columns if none are given.
+func pageColumns(view View, columns []string) ([]string, []int, error) {
+	all := view.Columns()
+	if len(columns) == 0 {
+		table, ok := view.(Table)
+		if !ok {
+			return nil, nil, fmt.Errorf("reform: %s is not a table, columns for pagination should be specified", view.Name())
+		}
+		pks := table.PKColumnIndexes()
+		columns = make([]string, len(pks))
+		indexes := make([]int, len(pks))
+		for i, pk := range pks {
+			columns[i] = all[pk]
+			indexes[i] = int(pk)
+		}
+		return columns, indexes, nil
+	}
+
+	indexes := make([]int, len(columns))
+	for i, c := range columns {
+		indexes[i] = -1
+		for j, vc := range all {
+			if c == vc {
+				indexes[i] = j
+				break
+			}
+		}
+		if indexes[i] < 0 {
+			return nil, nil, fmt.Errorf("reform: %s has no column %s", view.Name(), c)
+		}
+	}
+	return columns, indexes, nil
+}
+
+// encodePageCursor returns cursor for the next page after given struct.
+func encodePageCursor(str Struct, columns []string, indexes []int) (string, error) {
+	values := str.Values()
+	c := pageCursor{
+		Columns: columns,
+		Values:  make([]json.RawMessage, len(indexes)),
+	}
+	for i, idx := range indexes {
+		b, err := json.Marshal(values[idx])
+		if err != nil {
+			return "", err
+		}
+		c.Values[i] = b
+	}
+
+	b, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// decodePageCursor returns values of cursor's columns with the same types as view's fields.
+func decodePageCursor(view View, cursor string, columns []string, indexes []int) ([]interface{}, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("reform: invalid cursor: %s", err)
+	}
+	var c pageCursor
+	if err = json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("reform: invalid cursor: %s", err)
+	}
+	if strings.Join(c.Columns, ",") != strings.Join(columns, ",") || len(c.Values) != len(columns) {
+		return nil, fmt.Errorf("reform: invalid cursor: columns %v, expected %v", c.Columns, columns)
+	}
+
+	// unmarshal values into a new struct's fields to get their types
+	str := view.NewStruct()
+	pointers := str.Pointers()
+	for i, idx := range indexes {
+		if err = json.Unmarshal(c.Values[i], pointers[idx]); err != nil {
+			return nil, fmt.Errorf("reform: invalid cursor: %s", err)
+		}
+	}
+
+	values := str.Values()
+	res := make([]interface{}, len(indexes))
+	for i, idx := range indexes {
+		res[i] = values[idx]
+	}
+	return res, nil
+}
+
+// pageCondition returns condition selecting rows after given values in columns order,
+// and arguments for it.
+func (q *Querier) pageCondition(columns []string, values []interface{}) (string, []interface{}) {
+	quoted := make([]string, len(columns))
+	for i, c := range columns {
+		quoted[i] = q.QuoteIdentifier(c)
+	}
+
+	if len(columns) == 1 {
+		return fmt.Sprintf("%s > %s", quoted[0], q.Placeholder(1)), values
+	}
+
+	if rowComparisonMethod(q.Dialect) == RowValues {
+		placeholders := make([]string, len(columns))
+		for i := range columns {
+			placeholders[i] = q.Placeholder(i + 1)
+		}
+		return fmt.Sprintf("(%s) > (%s)", strings.Join(quoted, ", "), strings.Join(placeholders, ", ")), values
+	}
+
+	// a > ? OR (a = ? AND b > ?) OR ...
+	var args []interface{}
+	disjuncts := make([]string, len(columns))
+	for i := range columns {
+		conjuncts := make([]string, i+1)
+		for j := 0; j < i; j++ {
+			args = append(args, values[j])
+			conjuncts[j] = fmt.Sprintf("%s = %s", quoted[j], q.Placeholder(len(args)))
+		}
+		args = append(args, values[i])
+		conjuncts[i] = fmt.Sprintf("%s > %s", quoted[i], q.Placeholder(len(args)))
+		disjuncts[i] = "(" + strings.Join(conjuncts, " AND ") + ")"
+	}
+	return "(" + strings.Join(disjuncts, " OR ") + ")", args
+}
+
+// SelectPage queries view using keyset (cursor) pagination. It returns up to size structs ordered by columns
+// (in ascending order) that follow the position encoded in cursor, and an opaque cursor for the next page.
+// If structs implement AfterFinder, it also calls AfterFind().
+//
+// If columns are empty, view should be a Table, and its primary key columns are used.
+// Columns should uniquely identify a row, and should not be NULL. Empty cursor means the first page.
+// Returned cursor is empty if there are no more rows.
+//
+// Depending on Dialect's RowComparisonMethod (see RowComparisonDialect), rows are filtered with "(a, b) > (?, ?)"
+// or expanded "a > ? OR (a = ? AND b > ?)" condition; they are limited with LIMIT or TOP.
+func (q *Querier) SelectPage(view View, columns []string, size int, cursor string) (structs []Struct, next string, err error) {
+	if size <= 0 {
+		return nil, "", fmt.Errorf("reform: invalid page size %d", size)
+	}
+
+	var indexes []int
+	if columns, indexes, err = pageColumns(view, columns); err != nil {
+		return
+	}
+
+	var tail string
+	var args []interface{}
+	if cursor != "" {
+		var values []interface{}
+		if values, err = decodePageCursor(view, cursor, columns, indexes); err != nil {
+			return
+		}
+		tail, args = q.pageCondition(columns, values)
+		tail = "WHERE " + tail
+	}
+
+	order := make([]string, len(columns))
+	for i, c := range columns {
+		order[i] = q.QuoteIdentifier(c)
+	}
+	tail += " ORDER BY " + strings.Join(order, ", ")
+
+	// select one more row to know if there is a next page
+	if q.SelectLimitMethod() == Limit {
+		tail += fmt.Sprintf(" LIMIT %d", size+1)
+	}
+
+	if structs, err = q.selectAll(view, q.selectQuery(view, tail, size+1), args...); err != nil {
+		return
+	}
+
+	if len(structs) > size {
+		structs = structs[:size]
+		next, err = encodePageCursor(structs[size-1], columns, indexes)
+	}
+	return
+}
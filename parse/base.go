@@ -13,9 +13,14 @@ import (
 
 // FieldInfo represents information about struct field.
 type FieldInfo struct {
-	Name   string // field name as defined in source file, e.g. Name
-	Type   string // field type as defined in source file, e.g. string; always present for primary key, may be absent otherwise
-	Column string // SQL database column name from "reform:" struct field tag, e.g. name
+	Name       string // field name as defined in source file, e.g. Name
+	Type       string // field type as defined in source file, e.g. string; always present for primary key, may be absent otherwise
+	Column     string // SQL database column name from "reform:" struct field tag, e.g. name
+	Version    bool   // true if field has "version" label in "reform:" struct field tag
+	SoftDelete bool   // true if field has "softdelete" label in "reform:" struct field tag
+	AutoCreate bool   // true if field has "autocreate" label in "reform:" struct field tag
+	AutoUpdate bool   // true if field has "autoupdate" label in "reform:" struct field tag
+	FK         string // referenced table and column from "fk=" label in "reform:" struct field tag, e.g. groups.id
 }
 
 // fieldInfoInSync returns true if FieldInfo fields that are set by both file and runtime parser are equal.
@@ -29,21 +34,59 @@ func fieldInfoInSync(fi1, fi2 *FieldInfo) bool {
 
 	return fi1.Name == fi2.Name &&
 		fi1.Type == fi2.Type &&
-		fi1.Column == fi2.Column
+		fi1.Column == fi2.Column &&
+		fi1.Version == fi2.Version &&
+		fi1.SoftDelete == fi2.SoftDelete &&
+		fi1.AutoCreate == fi2.AutoCreate &&
+		fi1.AutoUpdate == fi2.AutoUpdate &&
+		fi1.FK == fi2.FK
 }
 
 // GoString returns struct field information as Go code string.
 func (fi *FieldInfo) GoString() string {
-	return fmt.Sprintf("{Name: %q, Type: %q, Column: %q}", fi.Name, fi.Type, fi.Column)
+	res := fmt.Sprintf("{Name: %q, Type: %q, Column: %q", fi.Name, fi.Type, fi.Column)
+	if fi.Version {
+		res += ", Version: true"
+	}
+	if fi.SoftDelete {
+		res += ", SoftDelete: true"
+	}
+	if fi.AutoCreate {
+		res += ", AutoCreate: true"
+	}
+	if fi.AutoUpdate {
+		res += ", AutoUpdate: true"
+	}
+	if fi.FK != "" {
+		res += fmt.Sprintf(", FK: %q", fi.FK)
+	}
+	return res + "}"
+}
+
+// FKTable returns referenced table name from "fk=" label, or empty string.
+func (fi *FieldInfo) FKTable() string {
+	if i := strings.LastIndex(fi.FK, "."); i >= 0 {
+		return fi.FK[:i]
+	}
+	return ""
+}
+
+// FKColumn returns referenced column name from "fk=" label, or empty string.
+func (fi *FieldInfo) FKColumn() string {
+	if i := strings.LastIndex(fi.FK, "."); i >= 0 {
+		return fi.FK[i+1:]
+	}
+	return ""
 }
 
 // StructInfo represents information about struct.
 type StructInfo struct {
-	Type         string      // struct type as defined in source file, e.g. User
-	SQLSchema    string      // SQL database schema name from magic "reform:" comment, e.g. public
-	SQLName      string      // SQL database view or table name from magic "reform:" comment, e.g. users
-	Fields       []FieldInfo // fields info
-	PKFieldIndex int         // index of primary key field in Fields, -1 if none
+	Type           string      // struct type as defined in source file, e.g. User
+	SQLSchema      string      // SQL database schema name from magic "reform:" comment, e.g. public
+	SQLName        string      // SQL database view or table name from magic "reform:" comment, e.g. users
+	Fields         []FieldInfo // fields info
+	PKFieldIndex   int         // index of (first) primary key field in Fields, -1 if none
+	PKFieldIndexes []int       // indexes of all primary key fields in Fields, nil if none
 }
 
 // structInfoInSync returns true if FieldInfo fields that are set by both file and runtime parser are equal.
@@ -63,6 +106,15 @@ func structInfoInSync(si1, si2 *StructInfo) bool {
 		return false
 	}
 
+	if len(si1.PKFieldIndexes) != len(si2.PKFieldIndexes) {
+		return false
+	}
+	for i := range si1.PKFieldIndexes {
+		if si1.PKFieldIndexes[i] != si2.PKFieldIndexes[i] {
+			return false
+		}
+	}
+
 	if len(si1.Fields) != len(si2.Fields) {
 		return false
 	}
@@ -91,6 +143,9 @@ func (s *StructInfo) GoString() string {
 	res += "\t},\n"
 
 	res += fmt.Sprintf("\tPKFieldIndex: %d,\n", s.PKFieldIndex)
+	if s.PKFieldIndexes != nil {
+		res += fmt.Sprintf("\tPKFieldIndexes: %#v,\n", s.PKFieldIndexes)
+	}
 
 	res += "}"
 	return res
@@ -119,7 +174,12 @@ func (s *StructInfo) IsTable() bool {
 	return s.PKFieldIndex >= 0
 }
 
-// PKField returns a primary key field, panics for views.
+// IsCompositePK returns true if this object represent information for table with composite primary key.
+func (s *StructInfo) IsCompositePK() bool {
+	return len(s.PKFieldIndexes) > 1
+}
+
+// PKField returns a (first) primary key field, panics for views.
 func (s *StructInfo) PKField() FieldInfo {
 	if !s.IsTable() {
 		panic("reform: not a table")
@@ -127,6 +187,81 @@ func (s *StructInfo) PKField() FieldInfo {
 	return s.Fields[s.PKFieldIndex]
 }
 
+// PKFields returns all primary key fields, panics for views.
+func (s *StructInfo) PKFields() []FieldInfo {
+	if !s.IsTable() {
+		panic("reform: not a table")
+	}
+	res := make([]FieldInfo, len(s.PKFieldIndexes))
+	for i, pk := range s.PKFieldIndexes {
+		res[i] = s.Fields[pk]
+	}
+	return res
+}
+
+// VersionFieldIndex returns an index of field with "version" label in Fields, or -1 if none.
+func (s *StructInfo) VersionFieldIndex() int {
+	for i, f := range s.Fields {
+		if f.Version {
+			return i
+		}
+	}
+	return -1
+}
+
+// SoftDeleteFieldIndex returns an index of field with "softdelete" label in Fields, or -1 if none.
+func (s *StructInfo) SoftDeleteFieldIndex() int {
+	for i, f := range s.Fields {
+		if f.SoftDelete {
+			return i
+		}
+	}
+	return -1
+}
+
+// AutoCreateFieldIndexes returns indexes of fields with "autocreate" label in Fields.
+func (s *StructInfo) AutoCreateFieldIndexes() []int {
+	var res []int
+	for i, f := range s.Fields {
+		if f.AutoCreate {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// AutoUpdateFieldIndexes returns indexes of fields with "autoupdate" label in Fields.
+func (s *StructInfo) AutoUpdateFieldIndexes() []int {
+	var res []int
+	for i, f := range s.Fields {
+		if f.AutoUpdate {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// HasAutoTime returns true if struct has fields with "autocreate" or "autoupdate" labels.
+func (s *StructInfo) HasAutoTime() bool {
+	for _, f := range s.Fields {
+		if f.AutoCreate || f.AutoUpdate {
+			return true
+		}
+	}
+	return false
+}
+
+// FKFields returns fields with "fk=" label.
+func (s *StructInfo) FKFields() []FieldInfo {
+	var res []FieldInfo
+	for _, f := range s.Fields {
+		if f.FK != "" {
+			res = append(res, f)
+		}
+	}
+	return res
+}
+
 // AssertUpToDate checks that given StructInfo matches given object.
 // It is used during program initialization to check that generated files are up-to-date.
 func AssertUpToDate(si *StructInfo, obj interface{}) {
@@ -144,24 +279,80 @@ func AssertUpToDate(si *StructInfo, obj interface{}) {
 	}
 }
 
-// parseStructFieldTag is used by both file and runtime parsers
-func parseStructFieldTag(tag string) (sqlName string, isPK bool) {
+// fieldTag represents parsed "reform:" struct field tag.
+type fieldTag struct {
+	column     string
+	pk         bool
+	version    bool
+	softDelete bool
+	autoCreate bool
+	autoUpdate bool
+	fk         string
+}
+
+// parseStructFieldTag is used by both file and runtime parsers.
+// It returns zero value with empty column for invalid tags: unknown, duplicate or conflicting labels.
+// Only "pk" and "fk=" labels can be combined.
+func parseStructFieldTag(tag string) fieldTag {
 	parts := strings.Split(tag, ",")
-	if len(parts) == 0 || len(parts) > 2 {
-		return
+	res := fieldTag{column: parts[0]}
+	if res.column == "" {
+		return fieldTag{}
 	}
 
-	if len(parts) == 2 {
-		switch parts[1] {
+	var labels int // number of labels other than "fk="
+	for _, label := range parts[1:] {
+		switch label {
 		case "pk":
-			isPK = true
+			if res.pk {
+				return fieldTag{}
+			}
+			res.pk = true
+		case "version":
+			if res.version {
+				return fieldTag{}
+			}
+			res.version = true
+		case "softdelete":
+			if res.softDelete {
+				return fieldTag{}
+			}
+			res.softDelete = true
+		case "autocreate":
+			if res.autoCreate {
+				return fieldTag{}
+			}
+			res.autoCreate = true
+		case "autoupdate":
+			if res.autoUpdate {
+				return fieldTag{}
+			}
+			res.autoUpdate = true
 		default:
-			return
+			fk := strings.TrimPrefix(label, "fk=")
+			if fk == label || res.fk != "" {
+				return fieldTag{}
+			}
+			ref := strings.Split(fk, ".")
+			if len(ref) != 2 || ref[0] == "" || ref[1] == "" {
+				return fieldTag{}
+			}
+			res.fk = fk
+			continue
 		}
+		labels++
+	}
+
+	if labels > 1 || (res.fk != "" && labels == 1 && !res.pk) {
+		return fieldTag{}
 	}
+	return res
+}
 
-	sqlName = parts[0]
-	return
+// integerTypes contains Go types allowed for fields with "version" label.
+var integerTypes = map[string]struct{}{
+	"int": {}, "int8": {}, "int16": {}, "int32": {}, "int64": {},
+	"uint": {}, "uint8": {}, "uint16": {}, "uint32": {}, "uint64": {},
 }
 
 // checkFields is used by both file and runtime parsers
@@ -171,12 +362,42 @@ func checkFields(res *StructInfo) error {
 	}
 
 	dupes := make(map[string]string)
+	var version, softDelete string
 	for _, f := range res.Fields {
 		if f2, ok := dupes[f.Column]; ok {
 			return fmt.Errorf(`reform: %s has field %s with "reform:" tag with duplicate column name %s (used by %s), it is not allowed`,
 				res.Type, f.Name, f.Column, f2)
 		}
 		dupes[f.Column] = f.Name
+
+		if f.Version {
+			if _, ok := integerTypes[f.Type]; !ok {
+				return fmt.Errorf(`reform: %s has non-integer field %s with "version" label in "reform:" tag, it is not allowed`,
+					res.Type, f.Name)
+			}
+			if version != "" {
+				return fmt.Errorf(`reform: %s has field %s with "version" label in "reform:" tag (also used by %s), it is not allowed`,
+					res.Type, f.Name, version)
+			}
+			version = f.Name
+		}
+
+		if f.SoftDelete {
+			if f.Type != "*time.Time" {
+				return fmt.Errorf(`reform: %s has field %s of type other than *time.Time with "softdelete" label in "reform:" tag, it is not allowed`,
+					res.Type, f.Name)
+			}
+			if softDelete != "" {
+				return fmt.Errorf(`reform: %s has field %s with "softdelete" label in "reform:" tag (also used by %s), it is not allowed`,
+					res.Type, f.Name, softDelete)
+			}
+			softDelete = f.Name
+		}
+
+		if (f.AutoCreate || f.AutoUpdate) && f.Type != "time.Time" && f.Type != "*time.Time" {
+			return fmt.Errorf(`reform: %s has field %s of type other than time.Time or *time.Time with "autocreate" or "autoupdate" label in "reform:" tag, it is not allowed`,
+				res.Type, f.Name)
+		}
 	}
 
 	return nil
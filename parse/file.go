@@ -81,30 +81,35 @@ func parseStructTypeSpec(ts *ast.TypeSpec, str *ast.StructType) (*StructInfo, er
 		}
 
 		// parse tag and type
-		column, isPK := parseStructFieldTag(tag)
-		if column == "" {
+		ft := parseStructFieldTag(tag)
+		if ft.column == "" {
 			return nil, fmt.Errorf(`reform: %s has field %s with invalid "reform:" tag value, it is not allowed`, res.Type, name.Name)
 		}
 		typ := fileGoType(f.Type)
-		if isPK {
+		if ft.pk {
 			if strings.HasPrefix(typ, "*") {
 				return nil, fmt.Errorf(`reform: %s has pointer field %s with with "pk" label in "reform:" tag, it is not allowed`, res.Type, name.Name)
 			}
 			if strings.HasPrefix(typ, "[") {
 				return nil, fmt.Errorf(`reform: %s has slice field %s with with "pk" label in "reform:" tag, it is not allowed`, res.Type, name.Name)
 			}
-			if res.PKFieldIndex >= 0 {
-				return nil, fmt.Errorf(`reform: %s has field %s with with duplicate "pk" label in "reform:" tag (first used by %s), it is not allowed`, res.Type, name.Name, res.Fields[res.PKFieldIndex].Name)
-			}
 		}
 
 		res.Fields = append(res.Fields, FieldInfo{
-			Name:   name.Name,
-			Type:   typ,
-			Column: column,
+			Name:       name.Name,
+			Type:       typ,
+			Column:     ft.column,
+			Version:    ft.version,
+			SoftDelete: ft.softDelete,
+			AutoCreate: ft.autoCreate,
+			AutoUpdate: ft.autoUpdate,
+			FK:         ft.fk,
 		})
-		if isPK {
-			res.PKFieldIndex = n
+		if ft.pk {
+			if res.PKFieldIndex < 0 {
+				res.PKFieldIndex = n
+			}
+			res.PKFieldIndexes = append(res.PKFieldIndexes, n)
 		}
 		n++
 	}
@@ -2,6 +2,7 @@ package parse_test
 
 import (
 	"errors"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -28,7 +29,8 @@ var (
 			{Name: "CreatedAt", Type: "time.Time", Column: "created_at"},
 			{Name: "UpdatedAt", Type: "*time.Time", Column: "updated_at"},
 		},
-		PKFieldIndex: 0,
+		PKFieldIndex:   0,
+		PKFieldIndexes: []int{0},
 	}
 
 	project = StructInfo{
@@ -41,7 +43,8 @@ var (
 			{Name: "Start", Type: "time.Time", Column: "start"},
 			{Name: "End", Type: "*time.Time", Column: "end"},
 		},
-		PKFieldIndex: 1,
+		PKFieldIndex:   1,
+		PKFieldIndexes: []int{1},
 	}
 
 	personProject = StructInfo{
@@ -49,8 +52,8 @@ var (
 		SQLSchema: "",
 		SQLName:   "person_project",
 		Fields: []FieldInfo{
-			{Name: "PersonID", Type: "int32", Column: "person_id"},
-			{Name: "ProjectID", Type: "string", Column: "project_id"},
+			{Name: "PersonID", Type: "int32", Column: "person_id", FK: "people.id"},
+			{Name: "ProjectID", Type: "string", Column: "project_id", FK: "projects.id"},
 		},
 		PKFieldIndex: -1,
 	}
@@ -62,7 +65,8 @@ var (
 		Fields: []FieldInfo{
 			{Name: "ID", Type: "int32", Column: "id"},
 		},
-		PKFieldIndex: 0,
+		PKFieldIndex:   0,
+		PKFieldIndexes: []int{0},
 	}
 
 	constraints = StructInfo{
@@ -73,7 +77,8 @@ var (
 			{Name: "I", Type: "int32", Column: "i"},
 			{Name: "ID", Type: "string", Column: "id"},
 		},
-		PKFieldIndex: 1,
+		PKFieldIndex:   1,
+		PKFieldIndexes: []int{1},
 	}
 
 	compositePk = StructInfo{
@@ -88,6 +93,35 @@ var (
 		PKFieldIndex: -1,
 	}
 
+	compositePkRecord = StructInfo{
+		Type:      "CompositePkRecord",
+		SQLSchema: "",
+		SQLName:   "composite_pk",
+		Fields: []FieldInfo{
+			{Name: "I", Type: "int32", Column: "i"},
+			{Name: "Name", Type: "string", Column: "name"},
+			{Name: "J", Type: "string", Column: "j"},
+		},
+		PKFieldIndex:   0,
+		PKFieldIndexes: []int{0, 2},
+	}
+
+	document = StructInfo{
+		Type:      "Document",
+		SQLSchema: "",
+		SQLName:   "documents",
+		Fields: []FieldInfo{
+			{Name: "ID", Type: "int32", Column: "id"},
+			{Name: "Title", Type: "string", Column: "title"},
+			{Name: "Version", Type: "int32", Column: "version", Version: true},
+			{Name: "CreatedAt", Type: "time.Time", Column: "created_at", AutoCreate: true},
+			{Name: "UpdatedAt", Type: "*time.Time", Column: "updated_at", AutoUpdate: true},
+			{Name: "DeletedAt", Type: "*time.Time", Column: "deleted_at", SoftDelete: true},
+		},
+		PKFieldIndex:   0,
+		PKFieldIndexes: []int{0},
+	}
+
 	legacyPerson = StructInfo{
 		Type:      "LegacyPerson",
 		SQLSchema: "legacy",
@@ -96,7 +130,8 @@ var (
 			{Name: "ID", Type: "int32", Column: "id"},
 			{Name: "Name", Type: "*string", Column: "name"},
 		},
-		PKFieldIndex: 0,
+		PKFieldIndex:   0,
+		PKFieldIndexes: []int{0},
 	}
 
 	extra = StructInfo{
@@ -117,7 +152,8 @@ var (
 			{Name: "BytesT", Type: "Bytes", Column: "bytest"},
 			{Name: "Uint8sT", Type: "Uint8s", Column: "uint8st"},
 		},
-		PKFieldIndex: 0,
+		PKFieldIndex:   0,
+		PKFieldIndexes: []int{0},
 	}
 
 	notExported = StructInfo{
@@ -127,21 +163,24 @@ var (
 		Fields: []FieldInfo{
 			{Name: "ID", Type: "string", Column: "id"},
 		},
-		PKFieldIndex: 0,
+		PKFieldIndex:   0,
+		PKFieldIndexes: []int{0},
 	}
 )
 
 func TestFileGood(t *testing.T) {
 	s, err := File(filepath.FromSlash("../internal/test/models/good.go"))
 	assert.NoError(t, err)
-	require.Len(t, s, 7)
+	require.Len(t, s, 9)
 	assert.Equal(t, person, s[0])
 	assert.Equal(t, project, s[1])
 	assert.Equal(t, personProject, s[2])
 	assert.Equal(t, idOnly, s[3])
 	assert.Equal(t, constraints, s[4])
 	assert.Equal(t, compositePk, s[5])
-	assert.Equal(t, legacyPerson, s[6])
+	assert.Equal(t, compositePkRecord, s[6])
+	assert.Equal(t, document, s[7])
+	assert.Equal(t, legacyPerson, s[8])
 }
 
 func TestFileExtra(t *testing.T) {
@@ -165,8 +204,14 @@ func TestFileBogus(t *testing.T) {
 		// "bogus8.go": errors.New(`reform: Bogus8 has pointer field Bogus with with "omitempty" label in "reform:" tag, it is not allowed`),
 		"bogus8.go":  errors.New(`reform: Bogus8 has field Bogus with invalid "reform:" tag value, it is not allowed`),
 		"bogus9.go":  errors.New(`reform: Bogus9 has field Bogus2 with "reform:" tag with duplicate column name bogus (used by Bogus1), it is not allowed`),
-		"bogus10.go": errors.New(`reform: Bogus10 has field Bogus2 with with duplicate "pk" label in "reform:" tag (first used by Bogus1), it is not allowed`),
+		"bogus10.go": errors.New(`reform: Bogus10 has non-integer field Bogus with "version" label in "reform:" tag, it is not allowed`),
 		"bogus11.go": errors.New(`reform: Bogus11 has slice field Bogus with with "pk" label in "reform:" tag, it is not allowed`),
+		"bogus12.go": errors.New(`reform: Bogus12 has field Bogus of type other than *time.Time with "softdelete" label in "reform:" tag, it is not allowed`),
+		"bogus13.go": errors.New(`reform: Bogus13 has field Bogus of type other than time.Time or *time.Time with "autocreate" or "autoupdate" label in "reform:" tag, it is not allowed`),
+		"bogus14.go": errors.New(`reform: Bogus14 has field Bogus with invalid "reform:" tag value, it is not allowed`),
+		"bogus15.go": errors.New(`reform: Bogus15 has field Bogus with invalid "reform:" tag value, it is not allowed`),
+		"bogus16.go": errors.New(`reform: Bogus16 has field Bogus with invalid "reform:" tag value, it is not allowed`),
+		"bogus17.go": errors.New(`reform: Bogus17 has field Bogus with invalid "reform:" tag value, it is not allowed`),
 
 		"bogus_ignore.go": nil,
 	} {
@@ -201,6 +246,14 @@ func TestObjectGood(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, &compositePk, s)
 
+	s, err = Object(new(models.CompositePkRecord), "", "composite_pk")
+	assert.NoError(t, err)
+	assert.Equal(t, &compositePkRecord, s)
+
+	s, err = Object(new(models.Document), "", "documents")
+	assert.NoError(t, err)
+	assert.Equal(t, &document, s)
+
 	s, err = Object(new(models.LegacyPerson), "legacy", "people")
 	assert.NoError(t, err)
 	assert.Equal(t, &legacyPerson, s)
@@ -228,8 +281,14 @@ func TestObjectBogus(t *testing.T) {
 		// new(bogus.Bogus8): errors.New(`reform: Bogus8 has pointer field Bogus with with "omitempty" label in "reform:" tag, it is not allowed`),
 		new(bogus.Bogus8):  errors.New(`reform: Bogus8 has field Bogus with invalid "reform:" tag value, it is not allowed`),
 		new(bogus.Bogus9):  errors.New(`reform: Bogus9 has field Bogus2 with "reform:" tag with duplicate column name bogus (used by Bogus1), it is not allowed`),
-		new(bogus.Bogus10): errors.New(`reform: Bogus10 has field Bogus2 with with duplicate "pk" label in "reform:" tag (first used by Bogus1), it is not allowed`),
+		new(bogus.Bogus10): errors.New(`reform: Bogus10 has non-integer field Bogus with "version" label in "reform:" tag, it is not allowed`),
 		new(bogus.Bogus11): errors.New(`reform: Bogus11 has slice field Bogus with with "pk" label in "reform:" tag, it is not allowed`),
+		new(bogus.Bogus12): errors.New(`reform: Bogus12 has field Bogus of type other than *time.Time with "softdelete" label in "reform:" tag, it is not allowed`),
+		new(bogus.Bogus13): errors.New(`reform: Bogus13 has field Bogus of type other than time.Time or *time.Time with "autocreate" or "autoupdate" label in "reform:" tag, it is not allowed`),
+		new(bogus.Bogus14): errors.New(`reform: Bogus14 has field Bogus with invalid "reform:" tag value, it is not allowed`),
+		new(bogus.Bogus15): errors.New(`reform: Bogus15 has field Bogus with invalid "reform:" tag value, it is not allowed`),
+		new(bogus.Bogus16): errors.New(`reform: Bogus16 has field Bogus with invalid "reform:" tag value, it is not allowed`),
+		new(bogus.Bogus17): errors.New(`reform: Bogus17 has field Bogus with invalid "reform:" tag value, it is not allowed`),
 
 		// new(bogus.BogusIgnore): do not test,
 	} {
@@ -239,6 +298,48 @@ func TestObjectBogus(t *testing.T) {
 	}
 }
 
+// GroupMember is used for testing labels combination.
+type GroupMember struct {
+	GroupID  int32  `reform:"group_id,pk,fk=groups.id"`
+	PersonID int32  `reform:"person_id,fk=people.id,pk"`
+	Role     string `reform:"role"`
+}
+
+func TestMultipleLabels(t *testing.T) {
+	expected := StructInfo{
+		Type:      "GroupMember",
+		SQLSchema: "",
+		SQLName:   "group_members",
+		Fields: []FieldInfo{
+			{Name: "GroupID", Type: "int32", Column: "group_id", FK: "groups.id"},
+			{Name: "PersonID", Type: "int32", Column: "person_id", FK: "people.id"},
+			{Name: "Role", Type: "string", Column: "role"},
+		},
+		PKFieldIndex:   0,
+		PKFieldIndexes: []int{0, 1},
+	}
+
+	s, err := Object(new(GroupMember), "", "group_members")
+	require.NoError(t, err)
+	assert.Equal(t, &expected, s)
+
+	path := filepath.Join(t.TempDir(), "group_member.go")
+	src := `package models
+
+// GroupMember is used for testing labels combination. reform:group_members
+type GroupMember struct {
+	GroupID  int32  ` + "`" + `reform:"group_id,pk,fk=groups.id"` + "`" + `
+	PersonID int32  ` + "`" + `reform:"person_id,fk=people.id,pk"` + "`" + `
+	Role     string ` + "`" + `reform:"role"` + "`" + `
+}
+`
+	require.NoError(t, os.WriteFile(path, []byte(src), 0o644))
+	ss, err := File(path)
+	require.NoError(t, err)
+	require.Len(t, ss, 1)
+	assert.Equal(t, expected, ss[0])
+}
+
 func TestHelpersGood(t *testing.T) {
 	t.Parallel()
 
@@ -258,6 +359,7 @@ parse.StructInfo{
 		{Name: "UpdatedAt", Type: "*time.Time", Column: "updated_at"},
 	},
 	PKFieldIndex: 0,
+	PKFieldIndexes: []int{0},
 }`), person.GoString())
 		assert.Equal(t, []string{"id", "group_id", "name", "email", "created_at", "updated_at"}, person.Columns())
 		assert.Equal(t, strings.TrimSpace(`
@@ -287,6 +389,7 @@ parse.StructInfo{
 		{Name: "End", Type: "*time.Time", Column: "end"},
 	},
 	PKFieldIndex: 1,
+	PKFieldIndexes: []int{1},
 }`), project.GoString())
 		assert.Equal(t, []string{"name", "id", "start", "end"}, project.Columns())
 		assert.Equal(t, strings.TrimSpace(`
@@ -308,8 +411,8 @@ parse.StructInfo{
 	Type: "PersonProject",
 	SQLName: "person_project",
 	Fields: []parse.FieldInfo{
-		{Name: "PersonID", Type: "int32", Column: "person_id"},
-		{Name: "ProjectID", Type: "string", Column: "project_id"},
+		{Name: "PersonID", Type: "int32", Column: "person_id", FK: "people.id"},
+		{Name: "ProjectID", Type: "string", Column: "project_id", FK: "projects.id"},
 	},
 	PKFieldIndex: -1,
 }`), personProject.GoString())
@@ -320,6 +423,12 @@ parse.StructInfo{
 	"project_id",
 }`), personProject.ColumnsGoString())
 		assert.False(t, personProject.IsTable())
+		fks := personProject.FKFields()
+		require.Len(t, fks, 2)
+		assert.Equal(t, "people", fks[0].FKTable())
+		assert.Equal(t, "id", fks[0].FKColumn())
+		assert.Equal(t, "projects", fks[1].FKTable())
+		assert.Equal(t, "id", fks[1].FKColumn())
 	})
 
 	t.Run("idOnly", func(t *testing.T) {
@@ -333,6 +442,7 @@ parse.StructInfo{
 		{Name: "ID", Type: "int32", Column: "id"},
 	},
 	PKFieldIndex: 0,
+	PKFieldIndexes: []int{0},
 }`), idOnly.GoString())
 		assert.Equal(t, []string{"id"}, idOnly.Columns())
 		assert.Equal(t, strings.TrimSpace(`
@@ -355,6 +465,7 @@ parse.StructInfo{
 		{Name: "ID", Type: "string", Column: "id"},
 	},
 	PKFieldIndex: 1,
+	PKFieldIndexes: []int{1},
 }`), constraints.GoString())
 		assert.Equal(t, []string{"i", "id"}, constraints.Columns())
 		assert.Equal(t, strings.TrimSpace(`
@@ -390,6 +501,59 @@ parse.StructInfo{
 		assert.False(t, compositePk.IsTable())
 	})
 
+	t.Run("compositePkRecord", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, strings.TrimSpace(`
+parse.StructInfo{
+	Type: "CompositePkRecord",
+	SQLName: "composite_pk",
+	Fields: []parse.FieldInfo{
+		{Name: "I", Type: "int32", Column: "i"},
+		{Name: "Name", Type: "string", Column: "name"},
+		{Name: "J", Type: "string", Column: "j"},
+	},
+	PKFieldIndex: 0,
+	PKFieldIndexes: []int{0, 2},
+}`), compositePkRecord.GoString())
+		assert.Equal(t, []string{"i", "name", "j"}, compositePkRecord.Columns())
+		assert.True(t, compositePkRecord.IsTable())
+		assert.True(t, compositePkRecord.IsCompositePK())
+		assert.Equal(t, FieldInfo{Name: "I", Type: "int32", Column: "i"}, compositePkRecord.PKField())
+		assert.Equal(t, []FieldInfo{
+			{Name: "I", Type: "int32", Column: "i"},
+			{Name: "J", Type: "string", Column: "j"},
+		}, compositePkRecord.PKFields())
+	})
+
+	t.Run("document", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, strings.TrimSpace(`
+parse.StructInfo{
+	Type: "Document",
+	SQLName: "documents",
+	Fields: []parse.FieldInfo{
+		{Name: "ID", Type: "int32", Column: "id"},
+		{Name: "Title", Type: "string", Column: "title"},
+		{Name: "Version", Type: "int32", Column: "version", Version: true},
+		{Name: "CreatedAt", Type: "time.Time", Column: "created_at", AutoCreate: true},
+		{Name: "UpdatedAt", Type: "*time.Time", Column: "updated_at", AutoUpdate: true},
+		{Name: "DeletedAt", Type: "*time.Time", Column: "deleted_at", SoftDelete: true},
+	},
+	PKFieldIndex: 0,
+	PKFieldIndexes: []int{0},
+}`), document.GoString())
+		assert.Equal(t, 2, document.VersionFieldIndex())
+		assert.Equal(t, -1, legacyPerson.VersionFieldIndex())
+		assert.Equal(t, 5, document.SoftDeleteFieldIndex())
+		assert.Equal(t, -1, legacyPerson.SoftDeleteFieldIndex())
+		assert.True(t, document.HasAutoTime())
+		assert.Equal(t, []int{3}, document.AutoCreateFieldIndexes())
+		assert.Equal(t, []int{4}, document.AutoUpdateFieldIndexes())
+		assert.False(t, legacyPerson.HasAutoTime())
+	})
+
 	t.Run("legacyPerson", func(t *testing.T) {
 		t.Parallel()
 
@@ -403,6 +567,7 @@ parse.StructInfo{
 		{Name: "Name", Type: "*string", Column: "name"},
 	},
 	PKFieldIndex: 0,
+	PKFieldIndexes: []int{0},
 }`), legacyPerson.GoString())
 		assert.Equal(t, []string{"id", "name"}, legacyPerson.Columns())
 		assert.Equal(t, strings.TrimSpace(`
@@ -436,6 +601,7 @@ parse.StructInfo{
 		{Name: "Uint8sT", Type: "Uint8s", Column: "uint8st"},
 	},
 	PKFieldIndex: 0,
+	PKFieldIndexes: []int{0},
 }`), extra.GoString())
 		columns := []string{
 			"id", "name",
@@ -471,6 +637,7 @@ parse.StructInfo{
 		{Name: "ID", Type: "string", Column: "id"},
 	},
 	PKFieldIndex: 0,
+	PKFieldIndexes: []int{0},
 }`), notExported.GoString())
 		assert.Equal(t, []string{"id"}, notExported.Columns())
 		assert.Equal(t, strings.TrimSpace(`
@@ -499,4 +666,14 @@ func TestAssertUpToDate(t *testing.T) {
 		p.PKFieldIndex = 1
 		AssertUpToDate(&p, new(models.Person))
 	}()
+
+	func() {
+		defer func() {
+			assert.NotNil(t, recover())
+		}()
+
+		p := person
+		p.PKFieldIndexes = []int{0, 1}
+		AssertUpToDate(&p, new(models.Person))
+	}()
 }
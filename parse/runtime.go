@@ -63,30 +63,35 @@ func Object(obj interface{}, schema, table string) (res *StructInfo, err error)
 		}
 
 		// parse tag and type
-		column, isPK := parseStructFieldTag(tag)
-		if column == "" {
+		ft := parseStructFieldTag(tag)
+		if ft.column == "" {
 			return nil, fmt.Errorf(`reform: %s has field %s with invalid "reform:" tag value, it is not allowed`, res.Type, f.Name)
 		}
 		typ := objectGoType(f.Type, t)
-		if isPK {
+		if ft.pk {
 			if strings.HasPrefix(typ, "*") {
 				return nil, fmt.Errorf(`reform: %s has pointer field %s with with "pk" label in "reform:" tag, it is not allowed`, res.Type, f.Name)
 			}
 			if strings.HasPrefix(typ, "[") {
 				return nil, fmt.Errorf(`reform: %s has slice field %s with with "pk" label in "reform:" tag, it is not allowed`, res.Type, f.Name)
 			}
-			if res.PKFieldIndex >= 0 {
-				return nil, fmt.Errorf(`reform: %s has field %s with with duplicate "pk" label in "reform:" tag (first used by %s), it is not allowed`, res.Type, f.Name, res.Fields[res.PKFieldIndex].Name)
-			}
 		}
 
 		res.Fields = append(res.Fields, FieldInfo{
-			Name:   f.Name,
-			Type:   typ,
-			Column: column,
+			Name:       f.Name,
+			Type:       typ,
+			Column:     ft.column,
+			Version:    ft.version,
+			SoftDelete: ft.softDelete,
+			AutoCreate: ft.autoCreate,
+			AutoUpdate: ft.autoUpdate,
+			FK:         ft.fk,
 		})
-		if isPK {
-			res.PKFieldIndex = n
+		if ft.pk {
+			if res.PKFieldIndex < 0 {
+				res.PKFieldIndex = n
+			}
+			res.PKFieldIndexes = append(res.PKFieldIndexes, n)
 		}
 		n++
 	}
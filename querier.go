@@ -9,11 +9,29 @@ import (
 
 // Querier performs queries and commands.
 type Querier struct {
-	ctx     context.Context
-	dbtxCtx DBTXContext
-	tag     string
+	ctx         context.Context
+	dbtxCtx     DBTXContext
+	replicas    DBTXContext  // read replicas, if any; see reader
+	txStmts     *txStmts     // transaction-specific statements, if StmtCache is used by TX
+	idStep      *idStepCache // InsertMulti's primary key step, shared by DB and its TXs
+	tag         string
+	withDeleted bool
 	Dialect
 	Logger Logger
+
+	// StmtCache caches prepared statements for all queries sent to primary by Querier, DB or TX.
+	// If nil (default), query text is sent every time. TX started by DB with StmtCache uses the same cache,
+	// deriving transaction-specific statements from cached ones with tx.StmtContext (other queries are sent as is).
+	// Queries sent to read replicas are not cached. A cache must not be shared between DBs.
+	// Statements are removed from cache on connection errors; queries that can't be prepared are sent as is.
+	StmtCache *StmtCache
+
+	// Clock returns the current time used for soft delete and fields with "autocreate" and "autoupdate" labels.
+	// If nil, time.Now is used. Returned time is always converted to UTC.
+	Clock func() time.Time
+
+	// TimePrecision is used to truncate time returned by Clock. If zero, time is not truncated.
+	TimePrecision time.Duration
 }
 
 func newQuerier(ctx context.Context, dbtxCtx DBTXContext, tag string, dialect Dialect, logger Logger) *Querier {
@@ -23,11 +41,33 @@ func newQuerier(ctx context.Context, dbtxCtx DBTXContext, tag string, dialect Di
 		tag:     tag,
 		Dialect: dialect,
 		Logger:  logger,
+		idStep:  new(idStepCache),
 	}
 }
 
 func (q *Querier) clone() *Querier {
-	return newQuerier(q.ctx, q.dbtxCtx, q.tag, q.Dialect, q.Logger)
+	newQ := newQuerier(q.ctx, q.dbtxCtx, q.tag, q.Dialect, q.Logger)
+	newQ.replicas = q.replicas
+	newQ.txStmts = q.txStmts
+	newQ.idStep = q.idStep
+	newQ.StmtCache = q.StmtCache
+	newQ.withDeleted = q.withDeleted
+	newQ.Clock = q.Clock
+	newQ.TimePrecision = q.TimePrecision
+	return newQ
+}
+
+// now returns the current time using Querier's Clock and TimePrecision.
+func (q *Querier) now() time.Time {
+	clock := q.Clock
+	if clock == nil {
+		clock = time.Now
+	}
+	now := clock().UTC()
+	if q.TimePrecision > 0 {
+		now = now.Truncate(q.TimePrecision)
+	}
+	return now
 }
 
 func (q *Querier) logBefore(query string, args []interface{}) {
@@ -66,6 +106,40 @@ func (q *Querier) WithTag(format string, args ...interface{}) *Querier {
 	return newQ
 }
 
+// WithDeleted returns a copy of Querier that doesn't exclude soft-deleted rows from
+// selectors, finders, Count, Update and UpdateColumns. Returned Querier is tied to the same DB or TX.
+// See SoftDeleteView for details.
+func (q *Querier) WithDeleted() *Querier {
+	newQ := q.clone()
+	newQ.withDeleted = true
+	return newQ
+}
+
+// softDeleteColumn returns soft delete column name for given view,
+// or empty string if view doesn't have it.
+func softDeleteColumn(view View) string {
+	if sv, ok := view.(SoftDeleteView); ok {
+		return view.Columns()[sv.SoftDeleteColumnIndex()]
+	}
+	return ""
+}
+
+// selectFrom returns FROM clause target and columns qualifier for SELECT queries for given view.
+// For views with soft delete column, soft-deleted rows are excluded with derived table
+// (unless Querier is created by WithDeleted) aliased to unqualified view name,
+// so schema-qualified column names can't be used in tails.
+func (q *Querier) selectFrom(view View) (from, qualifier string) {
+	from = q.QualifiedView(view)
+	column := softDeleteColumn(view)
+	if column == "" || q.withDeleted {
+		return from, from
+	}
+
+	qualifier = q.QuoteIdentifier(view.Name())
+	from = fmt.Sprintf("(SELECT * FROM %s WHERE %s IS NULL) AS %s", from, q.QuoteIdentifier(column), qualifier)
+	return
+}
+
 // QualifiedView returns quoted qualified view name.
 func (q *Querier) QualifiedView(view View) string {
 	v := q.QuoteIdentifier(view.Name())
@@ -101,9 +175,13 @@ func (q *Querier) QualifiedColumns(view View) []string {
 // Exec executes a query without returning any rows.
 // The args are for any placeholder parameters in the query.
 func (q *Querier) Exec(query string, args ...interface{}) (sql.Result, error) {
+	query, args = q.expandIn(query, args)
 	q.logBefore(query, args)
 	start := time.Now()
-	res, err := q.dbtxCtx.ExecContext(q.ctx, query, args...)
+	dbtxCtx, release := q.target(q.dbtxCtx, query)
+	res, err := dbtxCtx.ExecContext(q.ctx, query, args...)
+	release()
+	q.invalidate(query, err)
 	q.logAfter(query, args, time.Since(start), err)
 	return res, err
 }
@@ -117,9 +195,18 @@ func (q *Querier) ExecContext(ctx context.Context, query string, args ...interfa
 // Query executes a query that returns rows, typically a SELECT.
 // The args are for any placeholder parameters in the query.
 func (q *Querier) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return q.queryOn(q.dbtxCtx, query, args...)
+}
+
+// queryOn executes a query that returns rows on given DBTXContext.
+func (q *Querier) queryOn(dbtxCtx DBTXContext, query string, args ...interface{}) (*sql.Rows, error) {
+	query, args = q.expandIn(query, args)
 	q.logBefore(query, args)
 	start := time.Now()
-	rows, err := q.dbtxCtx.QueryContext(q.ctx, query, args...)
+	dbtxCtx, release := q.target(dbtxCtx, query)
+	rows, err := dbtxCtx.QueryContext(q.ctx, query, args...)
+	release()
+	q.invalidate(query, err)
 	q.logAfter(query, args, time.Since(start), err)
 	return rows, err
 }
@@ -133,9 +220,18 @@ func (q *Querier) QueryContext(ctx context.Context, query string, args ...interf
 // QueryRow executes a query that is expected to return at most one row.
 // QueryRow always returns a non-nil value. Errors are deferred until Row's Scan method is called.
 func (q *Querier) QueryRow(query string, args ...interface{}) *sql.Row {
+	return q.queryRowOn(q.dbtxCtx, query, args...)
+}
+
+// queryRowOn executes a query that is expected to return at most one row on given DBTXContext.
+func (q *Querier) queryRowOn(dbtxCtx DBTXContext, query string, args ...interface{}) *sql.Row {
+	query, args = q.expandIn(query, args)
 	q.logBefore(query, args)
 	start := time.Now()
-	row := q.dbtxCtx.QueryRowContext(q.ctx, query, args...)
+	dbtxCtx, release := q.target(dbtxCtx, query)
+	row := dbtxCtx.QueryRowContext(q.ctx, query, args...)
+	release()
+	q.invalidate(query, row.Err())
 	q.logAfter(query, args, time.Since(start), nil)
 	return row
 }
@@ -1,8 +1,12 @@
 package reform
 
 import (
+	"database/sql"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 )
 
 func filteredColumnsAndValues(str Struct, columnsIn []string, isUpdate bool) (columns []string, values []interface{}, err error) {
@@ -19,15 +23,17 @@ func filteredColumnsAndValues(str Struct, columnsIn []string, isUpdate bool) (co
 	values = make([]interface{}, 0, len(columns))
 
 	record, _ := str.(Record)
-	var pk uint
+	pks := make(map[int]struct{})
 	if record != nil {
-		pk = view.(Table).PKColumnIndex()
+		for _, pk := range view.(Table).PKColumnIndexes() {
+			pks[int(pk)] = struct{}{}
+		}
 	}
 
 	for i, c := range allColumns {
 		if _, ok := columnsSet[c]; ok {
-			if isUpdate && record != nil && i == int(pk) {
-				err = fmt.Errorf("reform: will not update PK column: %s", c)
+			if _, ok = pks[i]; ok && isUpdate {
+				err = &PKUpdateError{Table: record.Table(), Column: c}
 				return
 			}
 			delete(columnsSet, c)
@@ -42,14 +48,51 @@ func filteredColumnsAndValues(str Struct, columnsIn []string, isUpdate bool) (co
 		for c := range columnsSet {
 			columns = append(columns, c)
 		}
-		// TODO make exported type for that error
-		err = fmt.Errorf("reform: unexpected columns: %v", columns)
+		sort.Strings(columns)
+		err = &UnexpectedColumnsError{View: view, Columns: columns}
 		return
 	}
 
 	return
 }
 
+// withoutPK cuts primary key columns with given (sorted) indexes from columns and values.
+// Both columns and values may be nil.
+func withoutPK(pks []uint, columns []string, values []interface{}) ([]string, []interface{}) {
+	for i := len(pks) - 1; i >= 0; i-- {
+		pk := pks[i]
+		if columns != nil {
+			columns = append(columns[:pk], columns[pk+1:]...)
+		}
+		if values != nil {
+			values = append(values[:pk], values[pk+1:]...)
+		}
+	}
+	return columns, values
+}
+
+// pkTail returns a tail of UPDATE or DELETE query for given table's primary key columns,
+// with placeholders starting from given index.
+func (q *Querier) pkTail(table Table, start int) string {
+	columns := table.Columns()
+	pks := table.PKColumnIndexes()
+	conditions := make([]string, len(pks))
+	for i, pk := range pks {
+		conditions[i] = q.QuoteIdentifier(columns[pk]) + " = " + q.Placeholder(start+i)
+	}
+	return "WHERE " + strings.Join(conditions, " AND ")
+}
+
+// singlePKRecord returns str as Record if it is a record with single-column primary key, nil otherwise.
+// Only such records have their primary key fields filled by INSERT.
+func singlePKRecord(str Struct) Record {
+	record, _ := str.(Record)
+	if record == nil || len(record.Table().PKColumnIndexes()) != 1 {
+		return nil
+	}
+	return record
+}
+
 func (q *Querier) insert(str Struct, columns []string, values []interface{}) error {
 	for i, c := range columns {
 		columns[i] = q.QuoteIdentifier(c)
@@ -57,7 +100,7 @@ func (q *Querier) insert(str Struct, columns []string, values []interface{}) err
 	placeholders := q.Placeholders(1, len(columns))
 
 	view := str.View()
-	record, _ := str.(Record)
+	record := singlePKRecord(str)
 	lastInsertIdMethod := q.LastInsertIdMethod()
 	defaultValuesMethod := q.DefaultValuesMethod()
 
@@ -85,7 +128,7 @@ func (q *Querier) insert(str Struct, columns []string, values []interface{}) err
 
 	switch lastInsertIdMethod {
 	case LastInsertId:
-		res, err := q.Exec(query, values...)
+		res, err := q.exec(view, query, values...)
 		if err != nil {
 			return err
 		}
@@ -105,9 +148,9 @@ func (q *Querier) insert(str Struct, columns []string, values []interface{}) err
 	case Returning, OutputInserted:
 		var err error
 		if record != nil {
-			err = q.QueryRow(query, values...).Scan(record.PKPointer())
+			err = q.queryRow(view, query, values, record.PKPointer())
 		} else {
-			_, err = q.Exec(query, values...)
+			_, err = q.exec(view, query, values...)
 		}
 		return err
 
@@ -116,68 +159,86 @@ func (q *Querier) insert(str Struct, columns []string, values []interface{}) err
 	}
 }
 
-func (q *Querier) beforeInsert(str Struct) error {
-	if bi, ok := str.(BeforeInserter); ok {
-		if err := bi.BeforeInsert(); err != nil {
-			return err
-		}
+// beforeInsert sets struct's auto time fields and calls BeforeInsert hook.
+// It returns names of auto time columns.
+func (q *Querier) beforeInsert(str Struct) ([]string, error) {
+	autoColumns := q.setAutoTime(str, true)
+
+	if err := q.callBeforeInsert(str); err != nil {
+		return nil, err
 	}
 
-	return nil
+	return autoColumns, nil
 }
 
 // Insert inserts a struct into SQL database table.
 // If str implements BeforeInserter, it calls BeforeInsert() before doing so.
+// If str implements AfterInserter, it calls AfterInsert() after that.
 //
-// It fills record's primary key field.
+// It fills record's primary key field if it is a single-column primary key.
+// Composite primary key fields are always inserted as is.
 func (q *Querier) Insert(str Struct) error {
-	if err := q.beforeInsert(str); err != nil {
+	if _, err := q.beforeInsert(str); err != nil {
 		return err
 	}
 
 	view := str.View()
 	values := str.Values()
 	columns := view.Columns()
-	record, _ := str.(Record)
+	record := singlePKRecord(str)
 
-	if record != nil {
-		pk := view.(Table).PKColumnIndex()
-
-		// cut primary key
-		if !record.HasPK() {
-			values = append(values[:pk], values[pk+1:]...)
-			columns = append(columns[:pk], columns[pk+1:]...)
-		}
+	// cut primary key
+	if record != nil && !record.HasPK() {
+		columns, values = withoutPK(record.Table().PKColumnIndexes(), columns, values)
 	}
 
-	return q.insert(str, columns, values)
+	if err := q.insert(str, columns, values); err != nil {
+		return err
+	}
+	q.snapshot(str, nil)
+	return q.callAfterInsert(str)
 }
 
 // InsertColumns inserts a struct into SQL database table with specified columns.
 // Other columns are omitted from generated INSERT statement.
 // If str implements BeforeInserter, it calls BeforeInsert() before doing so.
+// If str implements AfterInserter, it calls AfterInsert() after that.
 //
-// It fills record's primary key field.
+// Columns of fields with "autocreate" label are always inserted (see AutoTimeView).
+//
+// It fills record's primary key field if it is a single-column primary key.
 func (q *Querier) InsertColumns(str Struct, columns ...string) error {
-	if err := q.beforeInsert(str); err != nil {
+	autoColumns, err := q.beforeInsert(str)
+	if err != nil {
 		return err
 	}
 
-	columns, values, err := filteredColumnsAndValues(str, columns, false)
+	columns, values, err := filteredColumnsAndValues(str, withColumns(columns, autoColumns), false)
 	if err != nil {
 		return err
 	}
 
-	return q.insert(str, columns, values)
+	inserted := append([]string(nil), columns...)
+	if err = q.insert(str, columns, values); err != nil {
+		return err
+	}
+	q.snapshot(str, inserted)
+	return q.callAfterInsert(str)
 }
 
 // InsertMulti inserts several structs into SQL database table with single query.
 // If they implement BeforeInserter, it calls BeforeInsert() before doing so.
+// If they implement AfterInserter, it calls AfterInsert() after that.
 //
 // All structs should belong to the same view/table.
 // All records should either have or not have primary key set.
-// It doesn't fill primary key fields.
-// Given all these limitations, most users should use Querier.Insert in a loop, not this method.
+//
+// If records don't have single-column primary key set, it fills primary key fields using RETURNING clause,
+// assuming that rows are returned in the same order as VALUES (PostgreSQL and SQLite do that).
+// SQL Server doesn't guarantee the order of OUTPUT INSERTED rows, so primary key fields are not filled.
+// For dialects using LastInsertId method, primary keys are derived from sql.Result.LastInsertId()
+// according to Dialect's InsertMultiIdMethod (see InsertMultiIdDialect); for MySQL, it is done only
+// if innodb_autoinc_lock_mode is not "interleaved" (2), otherwise primary key fields are not filled.
 func (q *Querier) InsertMulti(structs ...Struct) error {
 	if len(structs) == 0 {
 		return nil
@@ -193,11 +254,9 @@ func (q *Querier) InsertMulti(structs ...Struct) error {
 
 	var err error
 	for _, str := range structs {
-		if bi, ok := str.(BeforeInserter); ok {
-			e := bi.BeforeInsert()
-			if err == nil {
-				err = e
-			}
+		q.setAutoTime(str, true)
+		if e := q.callBeforeInsert(str); err == nil {
+			err = e
 		}
 	}
 	if err != nil {
@@ -205,7 +264,7 @@ func (q *Querier) InsertMulti(structs ...Struct) error {
 	}
 
 	// check if all PK are present or all are absent
-	record, _ := structs[0].(Record)
+	record := singlePKRecord(structs[0])
 	if record != nil {
 		for _, str := range structs {
 			rec, _ := str.(Record)
@@ -221,36 +280,207 @@ func (q *Querier) InsertMulti(structs ...Struct) error {
 		columns[i] = q.QuoteIdentifier(c)
 	}
 
-	var pk uint
-	if record != nil && !record.HasPK() {
-		pk = view.(Table).PKColumnIndex()
-		columns = append(columns[:pk], columns[pk+1:]...)
+	var pks []uint
+	var pk string
+	fillPK := record != nil && !record.HasPK()
+	if fillPK {
+		pks = record.Table().PKColumnIndexes()
+		pk = columns[pks[0]]
+		columns, _ = withoutPK(pks, columns, nil)
 	}
 
+	lastInsertIdMethod := q.LastInsertIdMethod()
 	placeholders := q.Placeholders(1, len(columns)*len(structs))
-	query := fmt.Sprintf("%s INTO %s (%s) VALUES ",
+	query := fmt.Sprintf("%s INTO %s (%s)",
 		q.startQuery("INSERT"),
 		q.QualifiedView(view),
 		strings.Join(columns, ", "),
 	)
+	query += " VALUES "
 	for i := 0; i < len(structs); i++ {
 		query += fmt.Sprintf("(%s), ", strings.Join(placeholders[len(columns)*i:len(columns)*(i+1)], ", "))
 	}
 	query = query[:len(query)-2] // cut last ", "
+	if fillPK && lastInsertIdMethod == Returning {
+		query += " RETURNING " + pk
+	}
 
 	values := make([]interface{}, 0, len(placeholders))
 	for _, str := range structs {
-		v := str.Values()
-		if record != nil && !record.HasPK() {
-			v = append(v[:pk], v[pk+1:]...)
-		}
+		_, v := withoutPK(pks, nil, str.Values())
 		values = append(values, v...)
 	}
 
-	_, err = q.Exec(query, values...)
+	if err = q.execInsertMulti(structs, query, values, fillPK); err != nil {
+		return err
+	}
+
+	for _, str := range structs {
+		q.snapshot(str, nil)
+		if e := q.callAfterInsert(str); err == nil {
+			err = e
+		}
+	}
 	return err
 }
 
+// execInsertMulti executes InsertMulti query and fills primary keys if fillPK is true and possible.
+func (q *Querier) execInsertMulti(structs []Struct, query string, values []interface{}, fillPK bool) error {
+	var err error
+	switch q.LastInsertIdMethod() {
+	case LastInsertId:
+		var step int64
+		if fillPK {
+			if step, err = q.insertMultiIdStep(); err != nil {
+				return err
+			}
+		}
+
+		res, err := q.exec(structs[0].View(), query, values...)
+		if err != nil || step == 0 {
+			return err
+		}
+
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		if insertMultiIdMethod(q.Dialect) == LastRowInsertId {
+			id -= step * int64(len(structs)-1)
+		}
+		for _, str := range structs {
+			SetPK(str.(Record), id)
+			id += step
+		}
+		return nil
+
+	case Returning:
+		if !fillPK {
+			_, err = q.exec(structs[0].View(), query, values...)
+			return err
+		}
+		return q.insertMultiScanPKs(structs, query, values)
+
+	case OutputInserted:
+		// order of OUTPUT INSERTED rows is not guaranteed, so primary keys are not filled
+		_, err = q.exec(structs[0].View(), query, values...)
+		return err
+
+	default:
+		panic("reform: Unhandled LastInsertIdMethod. Please report this bug.")
+	}
+}
+
+// InsertBatch inserts several structs into SQL database table with InsertMulti,
+// splitting them into chunks so that a single query doesn't exceed Dialect's MaxParameters
+// (see MaxParametersDialect).
+// It returns a number of inserted structs. Chunks are inserted with separate queries, so
+// when InsertBatch is called not inside a transaction, it may insert only some of them in case of error;
+// DB's InsertBatchInTransaction may be used to prevent that.
+//
+// All limitations of InsertMulti apply.
+func (q *Querier) InsertBatch(structs ...Struct) (uint, error) {
+	if len(structs) == 0 {
+		return 0, nil
+	}
+
+	size := len(structs)
+	if max := maxParameters(q.Dialect); max > 0 {
+		size = max / len(structs[0].View().Columns())
+		if size == 0 {
+			size = 1
+		}
+	}
+
+	var inserted uint
+	for len(structs) > 0 {
+		chunk := structs
+		if len(chunk) > size {
+			chunk = chunk[:size]
+		}
+		if err := q.InsertMulti(chunk...); err != nil {
+			return inserted, err
+		}
+		inserted += uint(len(chunk))
+		structs = structs[len(chunk):]
+	}
+	return inserted, nil
+}
+
+// idStepCache caches a step returned by insertMultiIdStep query.
+type idStepCache struct {
+	m    sync.Mutex
+	step int64
+	ok   bool
+}
+
+// insertMultiIdStep returns a step between primary keys of rows inserted by a single multi-row INSERT
+// for dialects using LastInsertId method, or 0 if they can't be derived.
+// For MySQL, server variables are queried once per DB (and its TXs).
+func (q *Querier) insertMultiIdStep() (int64, error) {
+	switch insertMultiIdMethod(q.Dialect) {
+	case NoInsertMultiIds:
+		return 0, nil
+
+	case FirstInsertId:
+		c := q.idStep
+		if c == nil {
+			c = new(idStepCache)
+		}
+		c.m.Lock()
+		defer c.m.Unlock()
+		if c.ok {
+			return c.step, nil
+		}
+
+		var lockMode, increment int64
+		query := q.startQuery("SELECT") + " @@innodb_autoinc_lock_mode, @@auto_increment_increment"
+		if err := q.QueryRow(query).Scan(&lockMode, &increment); err != nil {
+			return 0, err
+		}
+		c.step = increment
+		if lockMode == 2 { // "interleaved": generated values may be not consecutive
+			c.step = 0
+		}
+		c.ok = true
+		return c.step, nil
+
+	case LastRowInsertId:
+		return 1, nil
+
+	default:
+		panic("reform: Unhandled InsertMultiIdMethod. Please report this bug.")
+	}
+}
+
+// insertMultiScanPKs executes InsertMulti query with RETURNING clause
+// and scans returned primary keys to records.
+func (q *Querier) insertMultiScanPKs(structs []Struct, query string, values []interface{}) (err error) {
+	var rows *sql.Rows
+	if rows, err = q.query(structs[0].View(), query, values...); err != nil {
+		return
+	}
+	defer func() {
+		e := rows.Close()
+		if err == nil {
+			err = e
+		}
+	}()
+
+	for i, str := range structs {
+		if !rows.Next() {
+			if err = rows.Err(); err == nil {
+				err = fmt.Errorf("reform: %d rows returned by INSERT of %d rows", i, len(structs))
+			}
+			return
+		}
+		if err = rows.Scan(str.(Record).PKPointer()); err != nil {
+			return
+		}
+	}
+	return rows.Err()
+}
+
 func (q *Querier) update(str Struct, columns []string, values []interface{}, tail string, args ...interface{}) (uint, error) {
 	for i, c := range columns {
 		columns[i] = q.QuoteIdentifier(c)
@@ -270,7 +500,7 @@ func (q *Querier) update(str Struct, columns []string, values []interface{}, tai
 	)
 
 	args = append(values, args...)
-	res, err := q.Exec(query, args...)
+	res, err := q.exec(table, query, args...)
 	if err != nil {
 		return 0, err
 	}
@@ -281,97 +511,145 @@ func (q *Querier) update(str Struct, columns []string, values []interface{}, tai
 	return uint(ra), nil
 }
 
-func (q *Querier) beforeUpdate(str Struct) error {
-	if bu, ok := str.(BeforeUpdater); ok {
-		if err := bu.BeforeUpdate(); err != nil {
+// beforeUpdate sets struct's auto time fields and calls BeforeUpdate hook.
+// It returns names of auto time columns.
+func (q *Querier) beforeUpdate(str Struct) ([]string, error) {
+	autoColumns := q.setAutoTime(str, false)
+
+	if err := q.callBeforeUpdate(str); err != nil {
+		return nil, err
+	}
+
+	return autoColumns, nil
+}
+
+// updateRecord updates given columns of row specified by primary key with given values.
+// Soft-deleted row is not updated unless Querier is created by WithDeleted.
+// If record's table has version column, it also checks and increments version.
+// If all is true, it stores a snapshot of all record's values (see Snapshotter), otherwise only of updated columns.
+// Then it calls AfterUpdate hook.
+func (q *Querier) updateRecord(record Record, columns []string, values []interface{}, all bool) error {
+	table := record.Table()
+	args := record.PKValues()
+
+	var version *versionUpdate
+	if vt, ok := table.(VersionedTable); ok {
+		version = newVersionUpdate(record, vt.VersionColumnIndex())
+		columns, values = version.set(columns, values)
+	}
+
+	var updated []string
+	if !all {
+		updated = append(updated, columns...)
+	}
+
+	tail := q.pkTail(table, len(columns)+1)
+	if column := softDeleteColumn(table); column != "" && !q.withDeleted {
+		tail += fmt.Sprintf(" AND %s IS NULL", q.QuoteIdentifier(column))
+	}
+	if version != nil {
+		tail += fmt.Sprintf(" AND %s = %s", q.QuoteIdentifier(version.column), q.Placeholder(len(columns)+len(args)+1))
+		args = append(args, version.old)
+	}
+
+	ra, err := q.update(record, columns, values, tail, args...)
+	if err != nil {
+		return err
+	}
+	if ra > 1 {
+		return &AffectedRowsError{Table: table, Command: "UPDATE", Rows: int64(ra)}
+	}
+
+	if ra == 0 {
+		if version == nil {
+			return ErrNoRows
+		}
+
+		// check if row was deleted or updated concurrently; replicas may lag behind
+		var count int
+		if count, err = q.WithPrimary().Count(table, q.pkTail(table, 1), record.PKValues()...); err != nil {
 			return err
 		}
+		if count == 0 {
+			return ErrNoRows
+		}
+		return ErrStaleRecord
 	}
 
-	return nil
+	if version != nil {
+		version.commit()
+	}
+	q.snapshot(record, updated)
+	return q.callAfterUpdate(record)
 }
 
 // Update updates all columns of row specified by primary key in SQL database table with given record.
 // If record implements BeforeUpdater, it calls BeforeUpdate() before doing so.
+// If record implements AfterUpdater, it calls AfterUpdate() after that.
+//
+// If record's table has version column, it is incremented, and the row is updated only if its version matches.
+// If record's table has soft delete column, soft-deleted row is not updated unless Querier is created by WithDeleted.
 //
 // Method returns ErrNoRows if no rows were updated.
 // Method returns ErrNoPK if primary key is not set.
+// Method returns ErrStaleRecord if row's version doesn't match record's version.
 func (q *Querier) Update(record Record) error {
-	if err := q.beforeUpdate(record); err != nil {
+	if _, err := q.beforeUpdate(record); err != nil {
 		return err
 	}
 	if !record.HasPK() {
 		return ErrNoPK
 	}
 
+	// cut primary key
 	table := record.Table()
-	values := record.Values()
-	columns := table.Columns()
-
-	// cut primary key, make tail
-	pk := table.PKColumnIndex()
-	pkColumn := columns[pk]
-	values = append(values[:pk], values[pk+1:]...)
-	columns = append(columns[:pk], columns[pk+1:]...)
-	tail := fmt.Sprintf("WHERE %s = %s", q.QuoteIdentifier(pkColumn), q.Placeholder(len(columns)+1))
-
-	ra, err := q.update(record, columns, values, tail, record.PKValue())
-	if ra > 1 {
-		panic(fmt.Sprintf("reform: %d rows by UPDATE by primary key. Please report this bug.", ra))
-	}
-	if err == nil && ra == 0 {
-		err = ErrNoRows
-	}
-	return err
+	columns, values := withoutPK(table.PKColumnIndexes(), table.Columns(), record.Values())
+	return q.updateRecord(record, columns, values, true)
 }
 
 // UpdateColumns updates specified columns of row specified by primary key in SQL database table with given record.
 // Other columns are omitted from generated UPDATE statement.
 // If record implements BeforeUpdater, it calls BeforeUpdate() before doing so.
+// If record implements AfterUpdater, it calls AfterUpdate() after that.
+//
+// Columns of fields with "autoupdate" label are always updated (see AutoTimeView).
+// If record's table has version column, it is incremented, and the row is updated only if its version matches.
+// If record's table has soft delete column, soft-deleted row is not updated unless Querier is created by WithDeleted.
 //
 // Method returns ErrNoRows if no rows were updated.
 // Method returns ErrNoPK if primary key is not set.
+// Method returns ErrStaleRecord if row's version doesn't match record's version.
 func (q *Querier) UpdateColumns(record Record, columns ...string) error {
-	if err := q.beforeUpdate(record); err != nil {
+	autoColumns, err := q.beforeUpdate(record)
+	if err != nil {
 		return err
 	}
 	if !record.HasPK() {
 		return ErrNoPK
 	}
 
-	columns, values, err := filteredColumnsAndValues(record, columns, true)
-	if err != nil {
-		return err
+	if len(columns) == 0 {
+		return ErrNothingToUpdate
 	}
 
-	if len(values) == 0 {
-		// TODO make exported type for that error
-		return fmt.Errorf("reform: nothing to update")
+	columns, values, err := filteredColumnsAndValues(record, withColumns(columns, autoColumns), true)
+	if err != nil {
+		return err
 	}
 
-	// make tail
-	table := record.Table()
-	pkColumn := table.Columns()[table.PKColumnIndex()]
-	tail := fmt.Sprintf("WHERE %s = %s", q.QuoteIdentifier(pkColumn), q.Placeholder(len(columns)+1))
-
-	ra, err := q.update(record, columns, values, tail, record.PKValue())
-	if ra > 1 {
-		panic(fmt.Sprintf("reform: %d rows by UPDATE by primary key. Please report this bug.", ra))
-	}
-	if err == nil && ra == 0 {
-		err = ErrNoRows
-	}
-	return err
+	return q.updateRecord(record, columns, values, false)
 }
 
 // UpdateView updates specified columns of rows specified by tail and args in SQL database table with given struct,
 // and returns a number of updated rows.
 // Other columns are omitted from generated UPDATE statement.
 // If struct implements BeforeUpdater, it calls BeforeUpdate() before doing so.
+// Fields with "autoupdate" label are set (see AutoTimeView), but their columns are updated only if specified,
+// as tail's placeholders depend on the number of columns.
 //
 // Method never returns ErrNoRows.
 func (q *Querier) UpdateView(str Struct, columns []string, tail string, args ...interface{}) (uint, error) {
-	if err := q.beforeUpdate(str); err != nil {
+	if _, err := q.beforeUpdate(str); err != nil {
 		return 0, err
 	}
 
@@ -381,8 +659,11 @@ func (q *Querier) UpdateView(str Struct, columns []string, tail string, args ...
 	}
 
 	if len(values) == 0 {
-		// TODO make exported type for that error
-		return 0, fmt.Errorf("reform: nothing to update")
+		return 0, ErrNothingToUpdate
+	}
+
+	if tail, args, err = q.bindNamed(tail, len(values)+1, args); err != nil {
+		return 0, err
 	}
 
 	return q.update(str, columns, values, tail, args...)
@@ -392,36 +673,397 @@ func (q *Querier) UpdateView(str Struct, columns []string, tail string, args ...
 // If primary key is set, it first calls Update and checks if row was affected (matched).
 // If primary key is absent or no row was affected, it calls Insert. This allows to call Save with Record
 // with primary key set.
+// If record implements AfterSaver, it calls AfterSave() after successful Update or Insert.
 func (q *Querier) Save(record Record) error {
+	err := ErrNoRows
 	if record.HasPK() {
-		err := q.Update(record)
-		if err != ErrNoRows {
+		err = q.Update(record)
+	}
+	if err == ErrNoRows {
+		err = q.Insert(record)
+	}
+	if err != nil {
+		return err
+	}
+
+	return q.callAfterSave(record)
+}
+
+// noopColumn returns a column for assigning to itself when Upsert has nothing to update:
+// the first column that is neither primary key nor conflict column, or the first non-primary key column,
+// or the first conflict column.
+func noopColumn(table Table, conflictColumns []string) string {
+	columns := table.Columns()
+	skip := make(map[string]struct{}, len(columns))
+	for _, pk := range table.PKColumnIndexes() {
+		skip[columns[pk]] = struct{}{}
+	}
+	var nonPK string
+	for _, c := range columns {
+		if _, ok := skip[c]; ok {
+			continue
+		}
+		if nonPK == "" {
+			nonPK = c
+		}
+		var conflict bool
+		for _, cc := range conflictColumns {
+			if c == cc {
+				conflict = true
+				break
+			}
+		}
+		if !conflict {
+			return c
+		}
+	}
+	if nonPK != "" {
+		return nonPK
+	}
+	return conflictColumns[0]
+}
+
+// upsertQuery returns a query for Upsert with quoted columns.
+// If pk is not empty, query also returns or sets (depending on LastInsertIdMethod) that primary key column.
+// If noop is not empty, that column is assigned to itself, so conflicting row is updated without changes.
+func (q *Querier) upsertQuery(method UpsertMethod, view View, columns, conflictColumns, updateColumns []string, pk, noop string) string {
+	placeholders := q.Placeholders(1, len(columns))
+
+	switch method {
+	case OnConflict:
+		set := make([]string, len(updateColumns))
+		for i, c := range updateColumns {
+			set[i] = c + " = EXCLUDED." + c
+		}
+		if noop != "" {
+			set = append(set, noop+" = "+q.QuoteIdentifier(view.Name())+"."+noop)
+		}
+		query := fmt.Sprintf("%s INTO %s (%s) VALUES (%s) ON CONFLICT (%s) DO UPDATE SET %s",
+			q.startQuery("INSERT"),
+			q.QualifiedView(view),
+			strings.Join(columns, ", "),
+			strings.Join(placeholders, ", "),
+			strings.Join(conflictColumns, ", "),
+			strings.Join(set, ", "),
+		)
+		if pk != "" && q.LastInsertIdMethod() == Returning {
+			query += " RETURNING " + pk
+		}
+		return query
+
+	case OnDuplicateKeyUpdate:
+		var set []string
+		if pk != "" && q.LastInsertIdMethod() == LastInsertId {
+			// make LastInsertId() return primary key of updated row too
+			set = append(set, fmt.Sprintf("%s = LAST_INSERT_ID(%s)", pk, pk))
+		}
+		for _, c := range updateColumns {
+			set = append(set, fmt.Sprintf("%s = VALUES(%s)", c, c))
+		}
+		if noop != "" {
+			set = append(set, noop+" = "+noop)
+		}
+		return fmt.Sprintf("%s INTO %s (%s) VALUES (%s) ON DUPLICATE KEY UPDATE %s",
+			q.startQuery("INSERT"),
+			q.QualifiedView(view),
+			strings.Join(columns, ", "),
+			strings.Join(placeholders, ", "),
+			strings.Join(set, ", "),
+		)
+
+	case Merge:
+		target, source := q.QuoteIdentifier("target"), q.QuoteIdentifier("source")
+		on := make([]string, len(conflictColumns))
+		for i, c := range conflictColumns {
+			on[i] = fmt.Sprintf("%s.%s = %s.%s", target, c, source, c)
+		}
+		set := make([]string, len(updateColumns))
+		for i, c := range updateColumns {
+			set[i] = fmt.Sprintf("%s.%s = %s.%s", target, c, source, c)
+		}
+		if noop != "" {
+			set = append(set, fmt.Sprintf("%s.%s = %s.%s", target, noop, target, noop))
+		}
+		values := make([]string, len(columns))
+		for i, c := range columns {
+			values[i] = source + "." + c
+		}
+		query := fmt.Sprintf("%s INTO %s WITH (HOLDLOCK) AS %s USING (VALUES (%s)) AS %s (%s) ON %s "+
+			"WHEN MATCHED THEN UPDATE SET %s WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s)",
+			q.startQuery("MERGE"),
+			q.QualifiedView(view),
+			target,
+			strings.Join(placeholders, ", "),
+			source,
+			strings.Join(columns, ", "),
+			strings.Join(on, " AND "),
+			strings.Join(set, ", "),
+			strings.Join(columns, ", "),
+			strings.Join(values, ", "),
+		)
+		if pk != "" && q.LastInsertIdMethod() == OutputInserted {
+			query += " OUTPUT INSERTED." + pk
+		}
+		return query + ";"
+
+	default:
+		panic("reform: Unhandled UpsertMethod. Please report this bug.")
+	}
+}
+
+// Upsert inserts a record into SQL database table, or updates existing row conflicting with it
+// by conflictColumns, using a single query.
+// If conflictColumns is empty, primary key columns are used.
+// If updateColumns is empty, all columns except primary key, conflict columns and columns of fields
+// with "autocreate" label are updated; if there are no such columns, conflicting row is left unchanged
+// (non-key column is assigned to itself, preferably).
+// If record implements BeforeInserter, it calls BeforeInsert() before doing so.
+//
+// Fields with "autocreate" and "autoupdate" labels are set (see AutoTimeView); as the same values are used
+// for both inserting and updating, "autoupdate" fields are set for inserted rows too.
+// Columns of fields with "autoupdate" label are always updated.
+// If record implements AfterInserter and AfterSaver, it calls AfterInsert() and AfterSave() after that,
+// whether the row was inserted or updated.
+//
+// Version column (see VersionedTable) is neither checked nor incremented: record's version is inserted
+// or updated as is. Use Update or UpdateColumns for optimistic locking.
+//
+// Generated query depends on Dialect's UpsertMethod (see UpsertDialect); Upsert returns error for dialects
+// without it. Please note that MySQL's "ON DUPLICATE KEY UPDATE" handles conflicts on any primary key
+// or unique index, not only on conflictColumns.
+//
+// It fills record's primary key field if it is a single-column primary key.
+func (q *Querier) Upsert(record Record, conflictColumns []string, updateColumns ...string) error {
+	if err := q.upsert(record, conflictColumns, updateColumns); err != nil {
+		return err
+	}
+
+	// if only some columns were updated, other columns of existing row are unknown
+	if len(updateColumns) == 0 {
+		q.snapshot(record, nil)
+	} else {
+		q.snapshot(record, updateColumns)
+	}
+
+	if err := q.callAfterInsert(record); err != nil {
+		return err
+	}
+	return q.callAfterSave(record)
+}
+
+// upsert implements Upsert without calling after hooks.
+func (q *Querier) upsert(record Record, conflictColumns []string, updateColumns []string) error {
+	method, ok := upsertMethod(q.Dialect)
+	if !ok {
+		return fmt.Errorf("reform: %s dialect does not support Upsert", q.Dialect)
+	}
+
+	autoUpdateColumns := q.setAutoTime(record, false)
+	autoCreateColumns, err := q.beforeInsert(record)
+	if err != nil {
+		return err
+	}
+
+	table := record.Table()
+	allColumns := table.Columns()
+	values := record.Values()
+	columns := table.Columns()
+	pkRecord := singlePKRecord(record)
+	fillPK := pkRecord != nil && !pkRecord.HasPK()
+
+	// cut primary key
+	if fillPK {
+		columns, values = withoutPK(table.PKColumnIndexes(), columns, values)
+	}
+
+	if len(conflictColumns) == 0 {
+		// unset primary key is generated by database and can't conflict with existing rows
+		if fillPK {
+			return q.insert(record, columns, values)
+		}
+
+		for _, pk := range table.PKColumnIndexes() {
+			conflictColumns = append(conflictColumns, allColumns[pk])
+		}
+	}
+
+	// check that conflict columns are inserted, collect their values
+	columnValues := make(map[string]interface{}, len(columns))
+	for i, c := range columns {
+		columnValues[c] = values[i]
+	}
+	conflictValues := make([]interface{}, len(conflictColumns))
+	for i, c := range conflictColumns {
+		v, ok := columnValues[c]
+		if !ok {
+			return fmt.Errorf("reform: conflict column %s is not inserted", c)
+		}
+		conflictValues[i] = v
+	}
+
+	if len(updateColumns) == 0 {
+		skip := make(map[string]struct{}, len(conflictColumns))
+		for _, c := range conflictColumns {
+			skip[c] = struct{}{}
+		}
+		for _, pk := range table.PKColumnIndexes() {
+			skip[allColumns[pk]] = struct{}{}
+		}
+		for _, c := range autoCreateColumns {
+			skip[c] = struct{}{}
+		}
+		for _, c := range columns {
+			if _, ok := skip[c]; !ok {
+				updateColumns = append(updateColumns, c)
+			}
+		}
+	} else {
+		updateColumns = withColumns(updateColumns, autoUpdateColumns)
+		if updateColumns, _, err = filteredColumnsAndValues(record, updateColumns, true); err != nil {
 			return err
 		}
 	}
 
-	return q.Insert(record)
+	// update something so conflicting row is returned by RETURNING or OUTPUT,
+	// but do not change it and do not touch primary key (it may be an identity column)
+	var noop string
+	if len(updateColumns) == 0 {
+		noop = q.QuoteIdentifier(noopColumn(table, conflictColumns))
+	}
+
+	quote := func(columns []string) []string {
+		res := make([]string, len(columns))
+		for i, c := range columns {
+			res[i] = q.QuoteIdentifier(c)
+		}
+		return res
+	}
+
+	var pk string
+	if fillPK {
+		pk = q.QuoteIdentifier(allColumns[table.PKColumnIndex()])
+	}
+	query := q.upsertQuery(method, table, quote(columns), quote(conflictColumns), quote(updateColumns), pk, noop)
+
+	switch q.LastInsertIdMethod() {
+	case LastInsertId:
+		res, err := q.exec(table, query, values...)
+		if err != nil || !fillPK {
+			return err
+		}
+
+		if method == OnDuplicateKeyUpdate {
+			id, err := res.LastInsertId()
+			if err != nil {
+				return err
+			}
+			SetPK(record, id)
+			return nil
+		}
+
+		// LastInsertId() is not changed on update, so find primary key by conflict columns
+		where := make([]string, len(conflictColumns))
+		for i, c := range quote(conflictColumns) {
+			where[i] = c + " = " + q.Placeholder(i+1)
+		}
+		query = fmt.Sprintf("%s %s FROM %s WHERE %s",
+			q.startQuery("SELECT"),
+			pk,
+			q.QualifiedView(table),
+			strings.Join(where, " AND "),
+		)
+		return q.queryRow(table, query, conflictValues, record.PKPointer())
+
+	case Returning, OutputInserted:
+		if fillPK {
+			return q.queryRow(table, query, values, record.PKPointer())
+		}
+		_, err := q.exec(table, query, values...)
+		return err
+
+	default:
+		panic("reform: Unhandled LastInsertIdMethod. Please report this bug.")
+	}
 }
 
 // Delete deletes record from SQL database table by primary key.
+// If table has soft delete column (see SoftDeleteView), it is set to the current time instead,
+// and record's field is updated; rows that are already soft-deleted are not affected.
+// Use HardDelete to delete such records.
+// If record implements BeforeDeleter, it calls BeforeDelete() before doing so.
+// If record implements AfterDeleter, it calls AfterDelete() after that.
 //
 // Method returns ErrNoRows if no rows were deleted.
 // Method returns ErrNoPK if primary key is not set.
 func (q *Querier) Delete(record Record) error {
+	return q.delete(record, true)
+}
+
+// HardDelete deletes record from SQL database table by primary key,
+// even if table has soft delete column.
+// If record implements BeforeDeleter, it calls BeforeDelete() before doing so.
+// If record implements AfterDeleter, it calls AfterDelete() after that.
+//
+// Method returns ErrNoRows if no rows were deleted.
+// Method returns ErrNoPK if primary key is not set.
+func (q *Querier) HardDelete(record Record) error {
+	return q.delete(record, false)
+}
+
+// delete calls BeforeDelete hook, deletes (or soft-deletes, if soft is true and table supports that) record,
+// and calls AfterDelete hook.
+func (q *Querier) delete(record Record, soft bool) error {
+	if err := q.callBeforeDelete(record); err != nil {
+		return err
+	}
 	if !record.HasPK() {
 		return ErrNoPK
 	}
 
+	var err error
+	if sv, ok := record.Table().(SoftDeleteView); ok && soft {
+		err = q.softDelete(record, sv.SoftDeleteColumnIndex())
+	} else {
+		err = q.hardDelete(record)
+	}
+	if err != nil {
+		return err
+	}
+
+	return q.callAfterDelete(record)
+}
+
+// softDelete sets record's soft delete column with given index to the current time.
+func (q *Querier) softDelete(record Record, index uint) error {
+	table := record.Table()
+	column := table.Columns()[index]
+	tail := fmt.Sprintf("%s AND %s IS NULL", q.pkTail(table, 2), q.QuoteIdentifier(column))
+	now := q.now()
+	ra, err := q.update(record, []string{column}, []interface{}{now}, tail, record.PKValues()...)
+	if err != nil {
+		return err
+	}
+	if ra == 0 {
+		return ErrNoRows
+	}
+	if ra > 1 {
+		return &AffectedRowsError{Table: table, Command: "UPDATE", Rows: int64(ra)}
+	}
+
+	*(record.Pointers()[index].(**time.Time)) = &now
+	return nil
+}
+
+// hardDelete deletes record from SQL database table by primary key.
+func (q *Querier) hardDelete(record Record) error {
 	table := record.Table()
-	pk := table.PKColumnIndex()
-	query := fmt.Sprintf("%s FROM %s WHERE %s = %s",
+	query := fmt.Sprintf("%s FROM %s %s",
 		q.startQuery("DELETE"),
 		q.QualifiedView(table),
-		q.QuoteIdentifier(table.Columns()[pk]),
-		q.Placeholder(1),
+		q.pkTail(table, 1),
 	)
 
-	res, err := q.Exec(query, record.PKValue())
+	res, err := q.exec(table, query, record.PKValues()...)
 	if err != nil {
 		return err
 	}
@@ -433,7 +1075,7 @@ func (q *Querier) Delete(record Record) error {
 		return ErrNoRows
 	}
 	if ra > 1 {
-		panic(fmt.Sprintf("reform: %d rows by DELETE by primary key. Please report this bug.", ra))
+		return &AffectedRowsError{Table: table, Command: "DELETE", Rows: ra}
 	}
 	return nil
 }
@@ -442,13 +1084,18 @@ func (q *Querier) Delete(record Record) error {
 //
 // Method never returns ErrNoRows.
 func (q *Querier) DeleteFrom(view View, tail string, args ...interface{}) (uint, error) {
+	tail, args, err := q.bindNamed(tail, 1, args)
+	if err != nil {
+		return 0, err
+	}
+
 	query := fmt.Sprintf("%s FROM %s %s",
 		q.startQuery("DELETE"),
 		q.QualifiedView(view),
 		tail,
 	)
 
-	res, err := q.Exec(query, args...)
+	res, err := q.exec(view, query, args...)
 	if err != nil {
 		return 0, err
 	}
@@ -1,15 +1,23 @@
 package reform_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"testing"
 	"time"
 
 	"github.com/AlekSi/pointer"
 	"github.com/brianvoe/gofakeit/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"gopkg.in/reform.v1"
+	"gopkg.in/reform.v1/dialects/mssql"
+	"gopkg.in/reform.v1/dialects/mysql"
 	"gopkg.in/reform.v1/dialects/postgresql"
+	"gopkg.in/reform.v1/dialects/sqlserver"
 	. "gopkg.in/reform.v1/internal/test/models"
 )
 
@@ -147,17 +155,66 @@ func (s *ReformSuite) TestInsertMulti() {
 	err := s.q.InsertMulti(person1, person2)
 	s.NoError(err)
 
-	s.Equal(int32(0), person1.ID)
 	s.Equal("", person1.Name)
 	s.Equal(&newEmail, person1.Email)
 	s.WithinDuration(time.Now(), person1.CreatedAt, 2*time.Second)
 	s.Nil(person1.UpdatedAt)
 
-	s.Equal(int32(0), person2.ID)
 	s.Equal(newName, person2.Name)
 	s.Nil(person2.Email)
 	s.WithinDuration(time.Now(), person2.CreatedAt, 2*time.Second)
 	s.Nil(person2.UpdatedAt)
+
+	// MySQL with innodb_autoinc_lock_mode = 2 (default for 8.0) can't fill primary keys,
+	// SQL Server doesn't guarantee the order of OUTPUT INSERTED rows
+	if (s.q.Dialect == mysql.Dialect && person1.ID == 0) || s.q.LastInsertIdMethod() == reform.OutputInserted {
+		s.Equal(int32(0), person1.ID)
+		s.Equal(int32(0), person2.ID)
+		return
+	}
+
+	s.NotEqual(int32(0), person1.ID)
+	s.NotEqual(int32(0), person2.ID)
+	s.NotEqual(person1.ID, person2.ID)
+
+	person, err := s.q.FindByPrimaryKeyFrom(PersonTable, person1.ID)
+	s.NoError(err)
+	s.Equal(person1, person)
+
+	person, err = s.q.FindByPrimaryKeyFrom(PersonTable, person2.ID)
+	s.NoError(err)
+	s.Equal(person2, person)
+}
+
+func (s *ReformSuite) TestInsertMultiIdStepCached() {
+	if s.q.Dialect != mysql.Dialect {
+		s.T().Skip(s.q.Dialect.String() + " doesn't query server variables")
+	}
+
+	var queried int
+	q := s.q.WithTag("")
+	q.Logger = reform.NewPrintfLogger(func(format string, args ...interface{}) {
+		if strings.HasPrefix(format, ">>>") && strings.Contains(args[0].(string), "@@innodb_autoinc_lock_mode") {
+			queried++
+		}
+	})
+	for i := 0; i < 3; i++ {
+		s.NoError(q.InsertMulti(&Person{Name: gofakeit.Name()}, &Person{Name: gofakeit.Name()}))
+	}
+	s.LessOrEqual(queried, 1)
+}
+
+func (s *ReformSuite) TestInsertMultiWithoutInsertMultiIdDialect() {
+	if s.q.LastInsertIdMethod() != reform.LastInsertId {
+		s.T().Skip(s.q.Dialect.String() + " doesn't use LastInsertId")
+	}
+
+	q := *s.q
+	q.Dialect = minimalDialect{s.q.Dialect}
+	person1, person2 := &Person{Name: gofakeit.Name()}, &Person{Name: gofakeit.Name()}
+	s.NoError(q.InsertMulti(person1, person2))
+	s.Equal(int32(0), person1.ID)
+	s.Equal(int32(0), person2.ID)
 }
 
 func (s *ReformSuite) TestInsertMultiWithPrimaryKeys() {
@@ -283,9 +340,9 @@ func (s *ReformSuite) TestUpdateColumns() {
 
 	person := &Person{ID: 102, Name: newName, Email: &newEmail, CreatedAt: personCreated}
 	for e, columns := range map[error][]string{
-		errors.New("reform: unexpected columns: [foo]"):     {"foo"},
-		errors.New("reform: will not update PK column: id"): {"id"},
-		errors.New("reform: nothing to update"):             {},
+		&reform.UnexpectedColumnsError{View: PersonTable, Columns: []string{"foo"}}: {"foo"},
+		&reform.PKUpdateError{Table: PersonTable, Column: "id"}:                     {"id"},
+		reform.ErrNothingToUpdate:                                                   {},
 	} {
 		err := s.q.UpdateColumns(person, columns...)
 		s.Error(err)
@@ -328,9 +385,9 @@ func (s *ReformSuite) TestUpdateView() {
 
 	person := &Person{ID: 102, Name: newName, Email: &newEmail, CreatedAt: personCreated}
 	for e, columns := range map[error][]string{
-		errors.New("reform: unexpected columns: [foo]"):     {"foo"},
-		errors.New("reform: will not update PK column: id"): {"id"},
-		errors.New("reform: nothing to update"):             {},
+		&reform.UnexpectedColumnsError{View: PersonTable, Columns: []string{"foo"}}: {"foo"},
+		&reform.PKUpdateError{Table: PersonTable, Column: "id"}:                     {"id"},
+		reform.ErrNothingToUpdate:                                                   {},
 	} {
 		ra, err := s.q.UpdateView(person, columns, "")
 		s.Error(err)
@@ -435,3 +492,637 @@ func (s *ReformSuite) TestCommandsSchema() {
 	err = s.q.Delete(legacyPerson)
 	s.NoError(err)
 }
+
+func TestCompositePK(t *testing.T) {
+	db, tx := setupTX(t)
+	defer teardown(t, db)
+	defer func() {
+		assert.NoError(t, tx.Rollback())
+	}()
+
+	q := tx.Querier
+	assert.Equal(t, []uint{0, 2}, CompositePkRecordTable.PKColumnIndexes())
+
+	cpk := &CompositePkRecord{I: 1, Name: "first", J: "a"}
+	assert.True(t, cpk.HasPK())
+	assert.False(t, (&CompositePkRecord{I: 1}).HasPK())
+	assert.PanicsWithValue(t, "reform: CompositePkRecord has composite primary key, use PKValues", func() { cpk.PKValue() })
+	assert.PanicsWithValue(t, "reform: CompositePkRecord has composite primary key, use PKPointers", func() { cpk.PKPointer() })
+	assert.PanicsWithValue(t, "reform: CompositePkRecord has composite primary key, use PKPointers", func() { cpk.SetPK(1) })
+	assert.PanicsWithValue(t, "reform: composite_pk has composite primary key, use PKPointers", func() { reform.SetPK(cpk, 1) })
+	withIdentityInsert(t, q, "composite_pk", func() {
+		require.NoError(t, q.Insert(cpk))
+		require.NoError(t, q.Insert(&CompositePkRecord{I: 1, Name: "second", J: "b"}))
+	})
+	assert.Equal(t, &CompositePkRecord{I: 1, Name: "first", J: "a"}, cpk, "should not be changed")
+
+	found, err := q.FindByPrimaryKeyFrom(CompositePkRecordTable, []interface{}{int32(1), "b"})
+	require.NoError(t, err)
+	assert.Equal(t, &CompositePkRecord{I: 1, Name: "second", J: "b"}, found)
+
+	_, err = q.FindByPrimaryKeyFrom(CompositePkRecordTable, []interface{}{int32(1), "c"})
+	assert.Equal(t, reform.ErrNoRows, err)
+	_, err = q.FindByPrimaryKeyFrom(CompositePkRecordTable, int32(1))
+	assert.EqualError(t, err, "reform: composite_pk has composite primary key of 2 columns, got int32")
+
+	cpk.Name = "updated"
+	require.NoError(t, q.Update(cpk))
+	require.NoError(t, q.UpdateColumns(cpk, "name"))
+	assert.EqualError(t, q.UpdateColumns(cpk, "j"), "reform: will not update PK column: j")
+
+	reloaded := &CompositePkRecord{I: 1, J: "a"}
+	require.NoError(t, q.Reload(reloaded))
+	assert.Equal(t, cpk, reloaded)
+	found, err = q.FindByPrimaryKeyFrom(CompositePkRecordTable, []interface{}{int32(1), "b"})
+	require.NoError(t, err)
+	assert.Equal(t, "second", found.(*CompositePkRecord).Name, "other row should not be changed")
+
+	withIdentityInsert(t, q, "composite_pk", func() {
+		require.NoError(t, q.Save(&CompositePkRecord{I: 2, Name: "third", J: "a"}))
+	})
+	count, err := q.Count(CompositePkRecordTable, "")
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	require.NoError(t, q.Delete(cpk))
+	assert.Equal(t, reform.ErrNoRows, q.Delete(cpk))
+	assert.Equal(t, reform.ErrNoRows, q.Reload(cpk))
+	assert.Equal(t, reform.ErrNoPK, q.Delete(&CompositePkRecord{J: "b"}))
+
+	count, err = q.Count(CompositePkRecordTable, "")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestInsertBatch(t *testing.T) {
+	db, tx := setupTX(t)
+	defer teardown(t, db)
+	defer func() {
+		assert.NoError(t, tx.Rollback())
+	}()
+
+	q := tx.Querier
+
+	before, err := q.Count(PersonTable, "")
+	require.NoError(t, err)
+
+	// more than fits into a single query for any dialect
+	n := q.Dialect.(reform.MaxParametersDialect).MaxParameters()/len(PersonTable.Columns()) + 10
+	structs := make([]reform.Struct, n)
+	for i := range structs {
+		structs[i] = &Person{Name: fmt.Sprintf("Batch %d", i)}
+	}
+
+	inserted, err := q.InsertBatch(structs...)
+	require.NoError(t, err)
+	assert.Equal(t, uint(n), inserted)
+
+	after, err := q.Count(PersonTable, "")
+	require.NoError(t, err)
+	assert.Equal(t, before+n, after)
+
+	inserted, err = q.InsertBatch()
+	assert.NoError(t, err)
+	assert.Equal(t, uint(0), inserted)
+
+	// conservative limit is used for dialects without MaxParametersDialect
+	minimal := *q
+	minimal.Dialect = minimalDialect{q.Dialect}
+	var queries int
+	minimal.Logger = reform.NewPrintfLogger(func(format string, args ...interface{}) {
+		if strings.HasPrefix(format, ">>>") {
+			queries++
+		}
+	})
+	n = 999/len(PersonTable.Columns()) + 1
+	structs = make([]reform.Struct, n)
+	for i := range structs {
+		structs[i] = &Person{Name: fmt.Sprintf("Minimal %d", i)}
+	}
+	inserted, err = minimal.InsertBatch(structs...)
+	require.NoError(t, err)
+	assert.Equal(t, uint(n), inserted)
+	assert.Equal(t, 2, queries)
+}
+
+func TestOptimisticLocking(t *testing.T) {
+	db, tx := setupTX(t)
+	defer teardown(t, db)
+	defer func() {
+		assert.NoError(t, tx.Rollback())
+	}()
+
+	q := tx.Querier
+
+	doc := &Document{Title: "First"}
+	require.NoError(t, q.Insert(doc))
+	assert.Equal(t, int32(0), doc.Version)
+
+	doc.Title = "Second"
+	require.NoError(t, q.Update(doc))
+	assert.Equal(t, int32(1), doc.Version)
+
+	doc.Title = "Third"
+	require.NoError(t, q.UpdateColumns(doc, "title"))
+	assert.Equal(t, int32(2), doc.Version)
+
+	stale := &Document{ID: doc.ID}
+	require.NoError(t, q.Reload(stale))
+	assert.Equal(t, doc, stale)
+
+	// concurrent update
+	require.NoError(t, q.Update(doc))
+	assert.Equal(t, int32(3), doc.Version)
+
+	stale.Title = "Stale"
+	assert.Equal(t, reform.ErrStaleRecord, q.Update(stale))
+	assert.Equal(t, reform.ErrStaleRecord, q.UpdateColumns(stale, "title", "version"))
+	assert.Equal(t, int32(2), stale.Version)
+
+	require.NoError(t, q.Reload(stale))
+	assert.Equal(t, doc, stale)
+
+	require.NoError(t, q.HardDelete(doc))
+	assert.Equal(t, reform.ErrNoRows, q.Update(doc))
+	assert.Equal(t, int32(3), doc.Version)
+}
+
+func TestSoftDelete(t *testing.T) {
+	db, tx := setupTX(t)
+	defer teardown(t, db)
+	defer func() {
+		assert.NoError(t, tx.Rollback())
+	}()
+
+	q := tx.Querier
+
+	doc1, doc2 := &Document{Title: "First"}, &Document{Title: "Second"}
+	require.NoError(t, q.Insert(doc1))
+	require.NoError(t, q.Insert(doc2))
+
+	require.NoError(t, q.Delete(doc1))
+	require.NotNil(t, doc1.DeletedAt)
+	assert.WithinDuration(t, time.Now(), *doc1.DeletedAt, 2*time.Second)
+	assert.Equal(t, reform.ErrNoRows, q.Delete(doc1))
+
+	// soft-deleted rows are not updated, with either actual or stale version
+	staleDoc := *doc1
+	staleDoc.Version--
+	assert.Equal(t, reform.ErrNoRows, q.Update(doc1))
+	assert.Equal(t, reform.ErrNoRows, q.UpdateColumns(doc1, "title"))
+	assert.Equal(t, reform.ErrNoRows, q.Update(&staleDoc))
+
+	structs, err := q.SelectAllFrom(DocumentTable, "ORDER BY id")
+	require.NoError(t, err)
+	assert.Equal(t, []reform.Struct{doc2}, structs)
+
+	assert.Equal(t, reform.ErrNoRows, q.FindByPrimaryKeyTo(new(Document), doc1.ID))
+	assert.Equal(t, reform.ErrNoRows, q.FindOneTo(new(Document), "title", "First"))
+	count, err := q.Count(DocumentTable, "WHERE title = "+q.Placeholder(1), "First")
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	qd := q.WithDeleted()
+	structs, err = qd.SelectAllFrom(DocumentTable, "ORDER BY id")
+	require.NoError(t, err)
+	require.Len(t, structs, 2)
+	assert.Equal(t, doc2, structs[1])
+	assert.NotNil(t, structs[0].(*Document).DeletedAt)
+
+	assert.NoError(t, qd.FindByPrimaryKeyTo(new(Document), doc1.ID))
+	assert.NoError(t, qd.FindOneTo(new(Document), "title", "First"))
+	count, err = qd.Count(DocumentTable, "WHERE title = "+q.Placeholder(1), "First")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	// WithDeleted updates them, checking version
+	assert.Equal(t, reform.ErrStaleRecord, qd.Update(&staleDoc))
+	doc1.DeletedAt = nil
+	require.NoError(t, qd.Update(doc1))
+	assert.NoError(t, q.FindByPrimaryKeyTo(new(Document), doc1.ID))
+
+	require.NoError(t, q.HardDelete(doc1))
+	assert.Equal(t, reform.ErrNoRows, qd.Reload(doc1))
+}
+
+func TestAutoTime(t *testing.T) {
+	db, tx := setupTX(t)
+	defer teardown(t, db)
+	defer func() {
+		assert.NoError(t, tx.Rollback())
+	}()
+
+	q := tx.Querier
+	now := time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC)
+	q.Clock = func() time.Time { return now.Add(999 * time.Millisecond) }
+
+	doc := &Document{Title: "First"}
+	require.NoError(t, q.Insert(doc))
+	assert.Equal(t, now, doc.CreatedAt)
+	assert.Nil(t, doc.UpdatedAt)
+
+	created := now.Add(-time.Hour)
+	doc2 := &Document{Title: "Second", CreatedAt: created}
+	require.NoError(t, q.InsertColumns(doc2, "title"))
+	assert.Equal(t, created, doc2.CreatedAt)
+	require.NoError(t, q.Reload(doc2))
+	assert.Equal(t, created, doc2.CreatedAt)
+
+	now = now.Add(time.Minute)
+	require.NoError(t, q.Update(doc))
+	assert.Equal(t, now, doc.CreatedAt.Add(time.Minute))
+	require.NotNil(t, doc.UpdatedAt)
+	assert.Equal(t, now, *doc.UpdatedAt)
+
+	now = now.Add(time.Minute)
+	require.NoError(t, q.UpdateColumns(doc, "title"))
+	assert.Equal(t, now, *doc.UpdatedAt)
+
+	doc3 := &Document{ID: doc.ID}
+	require.NoError(t, q.Reload(doc3))
+	assert.Equal(t, doc, doc3)
+}
+
+// trackedDocument enables dirty tracking for Document.
+type trackedDocument struct {
+	*Document
+	reform.Tracker
+}
+
+func TestUpdateChanged(t *testing.T) {
+	db, tx := setupTX(t)
+	defer teardown(t, db)
+	defer func() {
+		assert.NoError(t, tx.Rollback())
+	}()
+
+	q := tx.Querier
+	now := time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC)
+	q.Clock = func() time.Time { return now }
+
+	doc := &trackedDocument{Document: &Document{Title: "First"}}
+	require.NoError(t, q.Insert(doc))
+	require.NotNil(t, doc.Snapshot())
+
+	found := &trackedDocument{Document: new(Document)}
+	require.NoError(t, q.FindByPrimaryKeyTo(found, doc.ID))
+	assert.Len(t, found.Snapshot(), len(DocumentTable.Columns()))
+
+	// nothing changed: no query, no autoupdate, no version increment
+	now = now.Add(time.Minute)
+	require.NoError(t, q.UpdateChanged(found))
+	assert.Nil(t, found.UpdatedAt)
+	assert.Equal(t, int32(0), found.Version)
+
+	// change created_at concurrently; it should not be overwritten
+	created := now.Add(-time.Hour)
+	_, err := q.UpdateView(&Document{CreatedAt: created}, []string{"created_at"}, "WHERE id = "+q.Placeholder(2), doc.ID)
+	require.NoError(t, err)
+
+	found.Title = "Changed"
+	require.NoError(t, q.UpdateChanged(found))
+	assert.Equal(t, int32(1), found.Version)
+	require.NotNil(t, found.UpdatedAt)
+	assert.Equal(t, now, *found.UpdatedAt)
+	require.NoError(t, q.UpdateChanged(found))
+	assert.Equal(t, int32(1), found.Version)
+
+	require.NoError(t, q.Reload(doc))
+	assert.Equal(t, "Changed", doc.Title)
+	assert.Equal(t, created, doc.CreatedAt)
+	assert.Equal(t, int32(1), doc.Version)
+
+	// without snapshot, all columns are updated
+	plain := &Document{ID: doc.ID, Title: "Plain", Version: 1, CreatedAt: now}
+	require.NoError(t, q.UpdateChanged(plain))
+	require.NoError(t, q.Reload(doc))
+	assert.Equal(t, "Plain", doc.Title)
+	assert.Equal(t, now, doc.CreatedAt)
+
+	// Upsert stores snapshot
+	upserted := &trackedDocument{Document: &Document{ID: doc.ID, Title: "Upserted", Version: doc.Version}}
+	require.NoError(t, q.Upsert(upserted, nil))
+	require.NotNil(t, upserted.Snapshot())
+	require.NoError(t, q.UpdateChanged(upserted))
+	assert.Equal(t, doc.Version, upserted.Version, "nothing should be updated")
+}
+
+// trackedPerson enables dirty tracking for Person, which normalizes times in AfterFind.
+type trackedPerson struct {
+	*Person
+	reform.Tracker
+}
+
+func TestUpdateChangedAfterFind(t *testing.T) {
+	db, tx := setupTX(t)
+	defer teardown(t, db)
+	defer func() {
+		assert.NoError(t, tx.Rollback())
+	}()
+
+	var logged int
+	q := tx.WithTag("")
+	q.Logger = reform.NewPrintfLogger(func(format string, args ...interface{}) { logged++ })
+
+	person := &trackedPerson{Person: new(Person)}
+	require.NoError(t, q.FindByPrimaryKeyTo(person, 102))
+	require.NotNil(t, person.Snapshot())
+
+	// the same time in other location is not a change
+	person.CreatedAt = person.CreatedAt.In(time.FixedZone("UTC+1", 3600))
+
+	logged = 0
+	require.NoError(t, q.UpdateChanged(person))
+	assert.Zero(t, logged, "no queries expected")
+	assert.Nil(t, person.UpdatedAt)
+
+	// the same for NextRow
+	rows, err := q.SelectRows(PersonTable, "WHERE id = "+q.Placeholder(1), 102)
+	require.NoError(t, err)
+	person = &trackedPerson{Person: new(Person)}
+	require.NoError(t, q.NextRow(person, rows))
+	require.NoError(t, rows.Close())
+
+	logged = 0
+	require.NoError(t, q.UpdateChanged(person))
+	assert.Zero(t, logged, "no queries expected")
+}
+
+// hookedDocument records calls of lifecycle hooks.
+type hookedDocument struct {
+	*Document
+	calls    []string
+	insertID int32
+	deleteOK bool
+}
+
+func (d *hookedDocument) AfterInsert() error {
+	d.calls = append(d.calls, "AfterInsert")
+	d.insertID = d.ID
+	return nil
+}
+
+func (d *hookedDocument) AfterUpdate() error {
+	d.calls = append(d.calls, "AfterUpdate")
+	return nil
+}
+
+func (d *hookedDocument) AfterSave() error {
+	d.calls = append(d.calls, "AfterSave")
+	return nil
+}
+
+func (d *hookedDocument) BeforeDelete() error {
+	d.calls = append(d.calls, "BeforeDelete")
+	if !d.deleteOK {
+		return errors.New("BeforeDelete: vetoed")
+	}
+	return nil
+}
+
+func (d *hookedDocument) AfterDelete() error {
+	d.calls = append(d.calls, "AfterDelete")
+	return nil
+}
+
+// check interfaces
+var (
+	_ reform.AfterInserter = (*hookedDocument)(nil)
+	_ reform.AfterUpdater  = (*hookedDocument)(nil)
+	_ reform.AfterSaver    = (*hookedDocument)(nil)
+	_ reform.BeforeDeleter = (*hookedDocument)(nil)
+	_ reform.AfterDeleter  = (*hookedDocument)(nil)
+)
+
+func TestHooks(t *testing.T) {
+	db, tx := setupTX(t)
+	defer teardown(t, db)
+	defer func() {
+		assert.NoError(t, tx.Rollback())
+	}()
+
+	q := tx.Querier
+
+	doc := &hookedDocument{Document: &Document{Title: "First"}}
+	require.NoError(t, q.Insert(doc))
+	assert.NotEqual(t, int32(0), doc.insertID)
+	assert.Equal(t, doc.ID, doc.insertID)
+	require.NoError(t, q.InsertColumns(&hookedDocument{Document: &Document{Title: "Second"}}, "title"))
+	require.NoError(t, q.Update(doc))
+	require.NoError(t, q.UpdateColumns(doc, "title"))
+	require.NoError(t, q.Save(doc))
+	assert.Equal(t, []string{"AfterInsert", "AfterUpdate", "AfterUpdate", "AfterUpdate", "AfterSave"}, doc.calls)
+
+	doc.calls = nil
+	assert.EqualError(t, q.Delete(doc), "BeforeDelete: vetoed")
+	assert.Nil(t, doc.DeletedAt)
+	doc.deleteOK = true
+	require.NoError(t, q.Delete(doc))
+	require.NoError(t, q.HardDelete(doc))
+	assert.Equal(t, []string{"BeforeDelete", "BeforeDelete", "AfterDelete", "BeforeDelete", "AfterDelete"}, doc.calls)
+
+	doc.calls = nil
+	doc.ID = 0
+	require.NoError(t, q.Save(doc))
+	assert.Equal(t, []string{"AfterInsert", "AfterSave"}, doc.calls)
+
+	doc.calls = nil
+	doc.Title = "Upserted"
+	require.NoError(t, q.Upsert(doc, nil))
+	assert.Equal(t, []string{"AfterInsert", "AfterSave"}, doc.calls)
+
+	docs := []reform.Struct{
+		&hookedDocument{Document: &Document{Title: "Third"}},
+		&hookedDocument{Document: &Document{Title: "Fourth"}},
+	}
+	require.NoError(t, q.InsertMulti(docs...))
+	for _, d := range docs {
+		d := d.(*hookedDocument)
+		assert.Equal(t, []string{"AfterInsert"}, d.calls)
+		assert.Equal(t, d.ID, d.insertID)
+	}
+}
+
+type contextKey string
+
+// contextHookedDocument implements both plain and context-aware hooks.
+type contextHookedDocument struct {
+	*Document
+}
+
+func (d *contextHookedDocument) BeforeInsert() error {
+	return errors.New("BeforeInsert should not be called")
+}
+
+func (d *contextHookedDocument) BeforeInsertContext(ctx context.Context, q *reform.Querier) error {
+	d.Title = ctx.Value(contextKey("tenant")).(string) + ": " + d.Title
+	return nil
+}
+
+func (d *contextHookedDocument) AfterInsertContext(ctx context.Context, q *reform.Querier) error {
+	// write audit row in the same transaction
+	return q.Insert(&Document{Title: "Inserted " + d.Title})
+}
+
+func (d *contextHookedDocument) AfterFind() error {
+	return errors.New("AfterFind should not be called")
+}
+
+func (d *contextHookedDocument) AfterFindContext(ctx context.Context, q *reform.Querier) error {
+	if ctx.Value(contextKey("tenant")) == nil {
+		return errors.New("AfterFindContext: no tenant")
+	}
+	return nil
+}
+
+// check interfaces
+var (
+	_ reform.BeforeInserterContext = (*contextHookedDocument)(nil)
+	_ reform.AfterInserterContext  = (*contextHookedDocument)(nil)
+	_ reform.AfterFinderContext    = (*contextHookedDocument)(nil)
+)
+
+func TestContextHooks(t *testing.T) {
+	db, tx := setupTX(t)
+	defer teardown(t, db)
+	defer func() {
+		assert.NoError(t, tx.Rollback())
+	}()
+
+	ctx := context.WithValue(context.Background(), contextKey("tenant"), "ACME")
+	q := tx.WithContext(ctx)
+
+	doc := &contextHookedDocument{Document: &Document{Title: "First"}}
+	require.NoError(t, q.Insert(doc))
+	assert.Equal(t, "ACME: First", doc.Title)
+
+	count, err := q.Count(DocumentTable, "WHERE title = "+q.Placeholder(1), "Inserted ACME: First")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	found := &contextHookedDocument{Document: new(Document)}
+	require.NoError(t, q.FindByPrimaryKeyTo(found, doc.ID))
+	assert.Equal(t, doc, found)
+
+	assert.EqualError(t, tx.FindByPrimaryKeyTo(found, doc.ID), "AfterFindContext: no tenant")
+}
+
+func TestUpsert(t *testing.T) {
+	db, tx := setupTX(t)
+	defer teardown(t, db)
+	defer func() {
+		assert.NoError(t, tx.Rollback())
+	}()
+
+	q := tx.Querier
+
+	t.Run("PrimaryKey", func(t *testing.T) {
+		person := &Person{Name: "New"}
+		require.NoError(t, q.Upsert(person, nil))
+		assert.NotEqual(t, int32(0), person.ID)
+
+		person2, err := q.FindByPrimaryKeyFrom(PersonTable, person.ID)
+		require.NoError(t, err)
+		assert.Equal(t, person, person2)
+
+		person.Name = "Updated"
+		require.NoError(t, q.Upsert(person, nil))
+		person2, err = q.FindByPrimaryKeyFrom(PersonTable, person.ID)
+		require.NoError(t, err)
+		assert.Equal(t, person, person2)
+
+		person = &Person{ID: 1, Name: "Updated 1"}
+		require.NoError(t, q.Upsert(person, nil, "name"))
+		person2, err = q.FindByPrimaryKeyFrom(PersonTable, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "Updated 1", person2.(*Person).Name)
+		assert.Equal(t, time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC), person2.(*Person).CreatedAt, "should not be changed")
+
+		withIdentityInsert(t, q, "people", func() {
+			require.NoError(t, q.Upsert(&Person{ID: 50, Name: "Fifty"}, nil))
+		})
+		person2, err = q.FindByPrimaryKeyFrom(PersonTable, 50)
+		require.NoError(t, err)
+		assert.Equal(t, "Fifty", person2.(*Person).Name)
+	})
+
+	t.Run("ConflictColumns", func(t *testing.T) {
+		require.NoError(t, q.Upsert(&Constraints{I: 1, ID: "a"}, nil))
+		require.NoError(t, q.Upsert(&Constraints{I: 2, ID: "a"}, nil))
+		require.NoError(t, q.Upsert(&Constraints{I: 2, ID: "b"}, []string{"i"}))
+
+		structs, err := q.SelectAllFrom(ConstraintsTable, "")
+		require.NoError(t, err)
+		assert.Equal(t, []reform.Struct{&Constraints{I: 2, ID: "a"}}, structs)
+	})
+
+	t.Run("NothingToUpdate", func(t *testing.T) {
+		q := q.WithTag("")
+		var queries []string
+		q.Logger = reform.NewPrintfLogger(func(format string, args ...interface{}) {
+			if strings.HasPrefix(format, ">>>") {
+				queries = append(queries, args[0].(string))
+			}
+		})
+
+		// conflicting row is not changed, primary key column is not assigned
+		require.NoError(t, q.Upsert(&Constraints{I: 2, ID: "c"}, []string{"i"}))
+		require.Len(t, queries, 1)
+		assert.NotContains(t, queries[0], q.QuoteIdentifier("id")+" =")
+		structs, err := q.SelectAllFrom(ConstraintsTable, "")
+		require.NoError(t, err)
+		assert.Equal(t, []reform.Struct{&Constraints{I: 2, ID: "a"}}, structs)
+
+		// conflict columns are primary key, and there is no other column
+		if q.Dialect == mssql.Dialect || q.Dialect == sqlserver.Dialect { //nolint:staticcheck
+			t.Skip("SQL Server can't update identity column")
+		}
+		var id IDOnly
+		require.NoError(t, q.Insert(&id))
+		require.NoError(t, q.Upsert(&IDOnly{ID: id.ID}, nil))
+		count, err := q.Count(IDOnlyTable, "WHERE id = "+q.Placeholder(1), id.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("AutoTime", func(t *testing.T) {
+		q := q.WithTag("")
+		now := time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC)
+		q.Clock = func() time.Time { return now }
+
+		doc := &Document{Title: "First"}
+		require.NoError(t, q.Insert(doc))
+		assert.Equal(t, now, doc.CreatedAt)
+
+		for _, updateColumns := range [][]string{nil, {"title"}} {
+			now = now.Add(time.Minute)
+			upserted := &Document{ID: doc.ID, Title: "Upserted"}
+			require.NoError(t, q.Upsert(upserted, nil, updateColumns...))
+			require.NotNil(t, upserted.UpdatedAt)
+			assert.Equal(t, now, *upserted.UpdatedAt)
+
+			found := &Document{ID: doc.ID}
+			require.NoError(t, q.Reload(found))
+			assert.Equal(t, "Upserted", found.Title)
+			assert.Equal(t, doc.CreatedAt, found.CreatedAt, "should not be changed")
+			require.NotNil(t, found.UpdatedAt)
+			assert.Equal(t, now, *found.UpdatedAt)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		err := q.Upsert(&Person{Name: "New"}, []string{"id"})
+		assert.EqualError(t, err, "reform: conflict column id is not inserted")
+
+		err = q.Upsert(&Constraints{I: 3, ID: "c"}, []string{"i"}, "id")
+		assert.EqualError(t, err, "reform: will not update PK column: id")
+
+		err = q.Upsert(&Constraints{I: 3, ID: "c"}, []string{"i"}, "no_such_column")
+		assert.EqualError(t, err, "reform: unexpected columns: [no_such_column]")
+
+		minimal := *q
+		minimal.Dialect = minimalDialect{q.Dialect}
+		err = minimal.Upsert(&Person{Name: "New"}, nil)
+		assert.EqualError(t, err, fmt.Sprintf("reform: %s dialect does not support Upsert", q.Dialect))
+	})
+}
@@ -161,12 +161,12 @@ func ExampleQuerier_InsertMulti() {
 		fmt.Printf("Inserted %d persons\n", len(batch))
 	}
 
-	// note that ID is not filled
-	fmt.Println(persons[0].(*Person).ID, persons[0].(*Person).Name)
+	// note that ID is filled if supported by database, see InsertMulti documentation
+	fmt.Println(persons[0].(*Person).Name)
 	// Output:
 	// Inserted 3 persons
 	// Inserted 2 persons
-	// 0 Alexey Palazhchenko
+	// Alexey Palazhchenko
 }
 
 func ExampleQuerier_Query() {
@@ -28,22 +28,30 @@ func (q *Querier) NextRow(str Struct, rows *sql.Rows) error {
 		return err
 	}
 
-	if af, ok := str.(AfterFinder); ok {
-		err = af.AfterFind()
+	if err = q.callAfterFind(str); err != nil {
+		return err
 	}
-	return err
+	q.snapshot(str, nil)
+	return nil
 }
 
 // selectQuery returns full SELECT query for given view and tail.
-func (q *Querier) selectQuery(view View, tail string, limit1 bool) string {
+// If top is positive and dialect uses SelectTop, rows are limited with TOP; other dialects should limit them in tail.
+func (q *Querier) selectQuery(view View, tail string, top int) string {
 	query := q.startQuery("SELECT")
 
-	if limit1 && q.SelectLimitMethod() == SelectTop {
-		query += " TOP 1"
+	if top > 0 && q.SelectLimitMethod() == SelectTop {
+		query += fmt.Sprintf(" TOP %d", top)
+	}
+
+	from, qualifier := q.selectFrom(view)
+	columns := view.Columns()
+	for i, c := range columns {
+		columns[i] = qualifier + "." + q.QuoteIdentifier(c)
 	}
 
 	return fmt.Sprintf("%s %s FROM %s %s",
-		query, strings.Join(q.QualifiedColumns(view), ", "), q.QualifiedView(view), tail)
+		query, strings.Join(columns, ", "), from, tail)
 }
 
 // SelectOneTo queries str's View with tail and args and scans first result to str.
@@ -52,14 +60,20 @@ func (q *Querier) selectQuery(view View, tail string, limit1 bool) string {
 // If there are no rows in result, it returns ErrNoRows. It also may return QueryRow(), Scan()
 // and AfterFinder errors.
 func (q *Querier) SelectOneTo(str Struct, tail string, args ...interface{}) error {
-	query := q.selectQuery(str.View(), tail, true)
-	if err := q.QueryRow(query, args...).Scan(str.Pointers()...); err != nil {
+	tail, args, err := q.bindNamed(tail, 1, args)
+	if err != nil {
 		return err
 	}
 
-	if af, ok := str.(AfterFinder); ok {
-		return af.AfterFind()
+	query := q.selectQuery(str.View(), tail, 1)
+	if err = q.readRow(str.View(), query, args, str.Pointers()...); err != nil {
+		return err
+	}
+
+	if err = q.callAfterFind(str); err != nil {
+		return err
 	}
+	q.snapshot(str, nil)
 	return nil
 }
 
@@ -83,8 +97,13 @@ func (q *Querier) SelectOneFrom(view View, tail string, args ...interface{}) (St
 //
 // See example for idiomatic usage.
 func (q *Querier) SelectRows(view View, tail string, args ...interface{}) (*sql.Rows, error) {
-	query := q.selectQuery(view, tail, false)
-	return q.Query(query, args...)
+	tail, args, err := q.bindNamed(tail, 1, args)
+	if err != nil {
+		return nil, err
+	}
+
+	query := q.selectQuery(view, tail, 0)
+	return q.read(view, query, args...)
 }
 
 // SelectAllFrom queries view with tail and args and returns a slice of new Structs.
@@ -92,9 +111,19 @@ func (q *Querier) SelectRows(view View, tail string, args ...interface{}) (*sql.
 //
 // In case of query error slice will be nil. If error is encountered during iteration,
 // partial result and error will be returned. Error is never ErrNoRows.
-func (q *Querier) SelectAllFrom(view View, tail string, args ...interface{}) (structs []Struct, err error) {
+func (q *Querier) SelectAllFrom(view View, tail string, args ...interface{}) ([]Struct, error) {
+	tail, args, err := q.bindNamed(tail, 1, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.selectAll(view, q.selectQuery(view, tail, 0), args...)
+}
+
+// selectAll executes SELECT query for given view and returns a slice of new Structs.
+func (q *Querier) selectAll(view View, query string, args ...interface{}) (structs []Struct, err error) {
 	var rows *sql.Rows
-	rows, err = q.SelectRows(view, tail, args...)
+	rows, err = q.read(view, query, args...)
 	if err != nil {
 		return
 	}
@@ -182,30 +211,71 @@ func (q *Querier) FindRows(view View, column string, arg interface{}) (*sql.Rows
 // In case of query error slice will be nil. If error is encountered during iteration,
 // partial result and error will be returned. Error is never ErrNoRows.
 func (q *Querier) FindAllFrom(view View, column string, args ...interface{}) ([]Struct, error) {
-	p := strings.Join(q.Placeholders(1, len(args)), ", ")
-	qi := q.QualifiedView(view) + "." + q.QuoteIdentifier(column)
-	tail := fmt.Sprintf("WHERE %s IN (%s)", qi, p)
-	return q.SelectAllFrom(view, tail, args...)
+	return q.SelectAllFrom(view, q.findAllTail(view, column, len(args)), args...)
+}
+
+// findAllTail returns a tail of SELECT query for given view, column and number of args.
+func (q *Querier) findAllTail(view View, column string, args int) string {
+	p := strings.Join(q.Placeholders(1, args), ", ")
+	qi := q.QuoteIdentifier(view.Name()) + "." + q.QuoteIdentifier(column)
+	return fmt.Sprintf("WHERE %s IN (%s)", qi, p)
+}
+
+// findByPKTail returns a tail of SELECT query for given table's composite primary key and values.
+func (q *Querier) findByPKTail(table Table, pk interface{}) (string, []interface{}, error) {
+	pks := table.PKColumnIndexes()
+	args, ok := pk.([]interface{})
+	if !ok || len(args) != len(pks) {
+		return "", nil, fmt.Errorf("reform: %s has composite primary key of %d columns, got %T", table.Name(), len(pks), pk)
+	}
+
+	v := q.QuoteIdentifier(table.Name())
+	columns := table.Columns()
+	conditions := make([]string, len(pks))
+	for i, c := range pks {
+		conditions[i] = fmt.Sprintf("%s.%s = %s", v, q.QuoteIdentifier(columns[c]), q.Placeholder(i+1))
+	}
+	tail := "WHERE " + strings.Join(conditions, " AND ")
+
+	if q.SelectLimitMethod() == Limit {
+		tail += " LIMIT 1"
+	}
+
+	return tail, args, nil
 }
 
 // FindByPrimaryKeyTo queries record's Table with primary key and scans first result to record.
 // If record implements AfterFinder, it also calls AfterFind().
 //
+// For tables with composite primary key, pk should be []interface{} with values
+// in the same order as Table's PKColumnIndexes (see Record's PKValues).
+//
 // If there are no rows in result, it returns ErrNoRows. It also may return QueryRow(), Scan()
 // and AfterFinder errors.
 func (q *Querier) FindByPrimaryKeyTo(record Record, pk interface{}) error {
 	table := record.Table()
-	return q.FindOneTo(record, table.Columns()[table.PKColumnIndex()], pk)
+	if len(table.PKColumnIndexes()) == 1 {
+		return q.FindOneTo(record, table.Columns()[table.PKColumnIndex()], pk)
+	}
+
+	tail, args, err := q.findByPKTail(table, pk)
+	if err != nil {
+		return err
+	}
+	return q.SelectOneTo(record, tail, args...)
 }
 
 // FindByPrimaryKeyFrom queries table with primary key and scans first result to new Record.
 // If record implements AfterFinder, it also calls AfterFind().
 //
+// For tables with composite primary key, pk should be []interface{} with values
+// in the same order as Table's PKColumnIndexes.
+//
 // If there are no rows in result, it returns nil, ErrNoRows. It also may return QueryRow(), Scan()
 // and AfterFinder errors.
 func (q *Querier) FindByPrimaryKeyFrom(table Table, pk interface{}) (Record, error) {
 	record := table.NewRecord()
-	if err := q.FindOneTo(record, table.Columns()[table.PKColumnIndex()], pk); err != nil {
+	if err := q.FindByPrimaryKeyTo(record, pk); err != nil {
 		return nil, err
 	}
 	return record, nil
@@ -213,14 +283,105 @@ func (q *Querier) FindByPrimaryKeyFrom(table Table, pk interface{}) (Record, err
 
 // Reload is a shortcut for FindByPrimaryKeyTo for given record.
 func (q *Querier) Reload(record Record) error {
-	return q.FindByPrimaryKeyTo(record, record.PKValue())
+	if len(record.Table().PKColumnIndexes()) == 1 {
+		return q.FindByPrimaryKeyTo(record, record.PKValue())
+	}
+	return q.FindByPrimaryKeyTo(record, record.PKValues())
 }
 
 // Count queries view with tail and args and returns a number (COUNT(*)) of matching rows.
 func (q *Querier) Count(view View, tail string, args ...interface{}) (int, error) {
-	query := fmt.Sprintf("%s COUNT(*) FROM %s %s", q.startQuery("SELECT"), q.QualifiedView(view), tail)
+	tail, args, err := q.bindNamed(tail, 1, args)
+	if err != nil {
+		return 0, err
+	}
+
+	from, _ := q.selectFrom(view)
+	query := fmt.Sprintf("%s COUNT(*) FROM %s %s", q.startQuery("SELECT"), from, tail)
+	var count int
+	if err = q.readRow(view, query, args, &count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Exists queries view with tail and args and returns true if there is at least one matching row.
+// It uses SELECT 1 query limited to a single row with LIMIT or TOP.
+func (q *Querier) Exists(view View, tail string, args ...interface{}) (bool, error) {
+	tail, args, err := q.bindNamed(tail, 1, args)
+	if err != nil {
+		return false, err
+	}
+
+	query := q.startQuery("SELECT")
+	if q.SelectLimitMethod() == SelectTop {
+		query += " TOP 1"
+	}
+	from, _ := q.selectFrom(view)
+	query = fmt.Sprintf("%s 1 FROM %s %s", query, from, tail)
+	if q.SelectLimitMethod() == Limit {
+		query += " LIMIT 1"
+	}
+
+	var one int
+	switch err := q.readRow(view, query, args, &one); err {
+	case nil:
+		return true, nil
+	case ErrNoRows:
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// aggregate queries view with tail and args and scans aggregate function result for column to dest.
+// If distinct is true, only distinct values are aggregated.
+func (q *Querier) aggregate(function string, distinct bool, view View, column string, dest interface{}, tail string, args ...interface{}) error {
+	tail, args, err := q.bindNamed(tail, 1, args)
+	if err != nil {
+		return err
+	}
+
+	expr := q.QuoteIdentifier(column)
+	if distinct {
+		expr = "DISTINCT " + expr
+	}
+	from, _ := q.selectFrom(view)
+	query := fmt.Sprintf("%s %s(%s) FROM %s %s", q.startQuery("SELECT"), function, expr, from, tail)
+	return q.readRow(view, query, args, dest)
+}
+
+// Sum queries view with tail and args and scans a sum (SUM(column)) of matching rows' column values to dest.
+// dest should be a pointer as for sql.Row.Scan. Result is NULL if there are no matching rows,
+// so dest should be a pointer to pointer or to sql.NullXXX type in that case.
+func (q *Querier) Sum(view View, column string, dest interface{}, tail string, args ...interface{}) error {
+	return q.aggregate("SUM", false, view, column, dest, tail, args...)
+}
+
+// Min queries view with tail and args and scans a minimal (MIN(column)) matching rows' column value to dest.
+// See Sum for dest requirements.
+func (q *Querier) Min(view View, column string, dest interface{}, tail string, args ...interface{}) error {
+	return q.aggregate("MIN", false, view, column, dest, tail, args...)
+}
+
+// Max queries view with tail and args and scans a maximal (MAX(column)) matching rows' column value to dest.
+// See Sum for dest requirements.
+func (q *Querier) Max(view View, column string, dest interface{}, tail string, args ...interface{}) error {
+	return q.aggregate("MAX", false, view, column, dest, tail, args...)
+}
+
+// Avg queries view with tail and args and scans an average (AVG(column)) of matching rows' column values to dest.
+// See Sum for dest requirements. Please note that result type for integer columns depends on SQL database:
+// some of them return integer, so use float64 or integer dest accordingly.
+func (q *Querier) Avg(view View, column string, dest interface{}, tail string, args ...interface{}) error {
+	return q.aggregate("AVG", false, view, column, dest, tail, args...)
+}
+
+// CountDistinct queries view with tail and args and returns a number (COUNT(DISTINCT column))
+// of distinct non-NULL column values of matching rows.
+func (q *Querier) CountDistinct(view View, column string, tail string, args ...interface{}) (int, error) {
 	var count int
-	if err := q.QueryRow(query, args...).Scan(&count); err != nil {
+	if err := q.aggregate("COUNT", true, view, column, &count, tail, args...); err != nil {
 		return 0, err
 	}
 	return count, nil
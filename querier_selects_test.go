@@ -1,11 +1,17 @@
 package reform_test
 
 import (
+	"errors"
+	"fmt"
+	"testing"
 	"time"
 
 	"github.com/AlekSi/pointer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"gopkg.in/reform.v1"
+	"gopkg.in/reform.v1/dialects/mysql"
 	"gopkg.in/reform.v1/dialects/postgresql"
 	. "gopkg.in/reform.v1/internal/test/models"
 )
@@ -305,4 +311,440 @@ func (s *ReformSuite) TestSelectsSchema() {
 		&LegacyPerson{ID: 1002, Name: pointer.ToString("Anastacio Ledner")},
 		&LegacyPerson{ID: 1003, Name: pointer.ToString("Dena Cummings")},
 	}, structs)
+
+	// soft-deleted rows are excluded with derived table aliased to unqualified table name
+	doc1, doc2 := &LegacyDocument{Title: "First"}, &LegacyDocument{Title: "Second"}
+	s.Require().NoError(s.q.Insert(doc1))
+	s.Require().NoError(s.q.Insert(doc2))
+	s.Require().NoError(s.q.Delete(doc1))
+	structs, err = s.q.SelectAllFrom(LegacyDocumentTable, "WHERE documents.title <> '' ORDER BY documents.id")
+	s.NoError(err)
+	s.Equal([]reform.Struct{doc2}, structs)
+	structs, err = s.q.WithDeleted().SelectAllFrom(LegacyDocumentTable, "WHERE legacy.documents.title <> ''")
+	s.NoError(err)
+	s.Len(structs, 2)
+}
+
+// personProjectRelations attaches people and projects to PersonProject.
+type personProjectRelations struct {
+	*PersonProject
+	person  *Person
+	project *Project
+}
+
+func (pp *personProjectRelations) SetRelation(column string, related reform.Struct) {
+	switch column {
+	case "person_id":
+		pp.person = related.(*Person)
+	case "project_id":
+		pp.project = related.(*Project)
+	}
+}
+
+// check interfaces
+var (
+	_ reform.RelationsView  = PersonProjectView
+	_ reform.RelationSetter = (*personProjectRelations)(nil)
+)
+
+func TestPreload(t *testing.T) {
+	db, tx := setupTX(t)
+	defer teardown(t, db)
+	defer func() {
+		assert.NoError(t, tx.Rollback())
+	}()
+
+	q := tx.Querier
+
+	res, err := q.SelectAllFrom(PersonProjectView, "ORDER BY project_id, person_id")
+	require.NoError(t, err)
+	require.Len(t, res, 6)
+	structs := make([]reform.Struct, len(res))
+	for i, str := range res {
+		structs[i] = &personProjectRelations{PersonProject: str.(*PersonProject)}
+	}
+
+	require.NoError(t, q.Preload(structs, "person_id", PersonTable))
+	require.NoError(t, q.Preload(structs, "project_id", ProjectTable))
+	for _, str := range structs {
+		pp := str.(*personProjectRelations)
+		require.NotNil(t, pp.person)
+		require.NotNil(t, pp.project)
+		assert.Equal(t, pp.PersonID, pp.person.ID)
+		assert.Equal(t, pp.ProjectID, pp.project.ID)
+	}
+	baron := structs[0].(*personProjectRelations)
+	assert.Equal(t, "Vicious Baron", baron.project.Name)
+	assert.Same(t, baron.project, structs[1].(*personProjectRelations).project)
+
+	assert.EqualError(t, q.Preload(structs, "name", PersonTable), "reform: person_project has no foreign key name")
+	assert.EqualError(t, q.Preload(structs, "person_id", ProjectTable),
+		"reform: foreign key person_project.person_id references people, got projects")
+	assert.EqualError(t, q.Preload(res, "person_id", PersonTable),
+		"reform: *models.PersonProject doesn't implement RelationSetter")
+	assert.NoError(t, q.Preload(nil, "person_id", PersonTable))
+}
+
+func TestSelectJoin(t *testing.T) {
+	db, tx := setupTX(t)
+	defer teardown(t, db)
+	defer func() {
+		assert.NoError(t, tx.Rollback())
+	}()
+
+	q := tx.Querier
+
+	views := []reform.View{PersonProjectView, PersonTable, ProjectTable}
+	tail := "JOIN people ON people.id = person_project.person_id " +
+		"JOIN projects ON projects.id = person_project.project_id " +
+		"WHERE projects.id = " + q.Placeholder(1) + " ORDER BY people.id"
+	rows, err := q.SelectJoin(views, tail, "queen")
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	for i, id := range []int32{102, 103} {
+		require.Len(t, rows[i], 3)
+		assert.Equal(t, &PersonProject{PersonID: id, ProjectID: "queen"}, rows[i][0])
+		person := rows[i][1].(*Person)
+		assert.Equal(t, id, person.ID)
+		assert.Equal(t, time.UTC, person.CreatedAt.Location(), "AfterFind should be called")
+		assert.Equal(t, &Project{ID: "queen", Name: "Thirsty Queen", Start: queenStart}, rows[i][2])
+	}
+
+	// self-join
+	tail = "JOIN people AS people_2 ON people_2.id = people.id + 1 WHERE people.id = " + q.Placeholder(1)
+	rows, err = q.SelectJoin([]reform.View{PersonTable, PersonTable}, tail, 102)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, int32(102), rows[0][0].(*Person).ID)
+	assert.Equal(t, int32(103), rows[0][1].(*Person).ID)
+
+	// named parameters
+	tail = "JOIN people AS people_2 ON people_2.id = people.id + 1 WHERE people.id = :id"
+	rows, err = q.SelectJoin([]reform.View{PersonTable, PersonTable}, tail, reform.Named(map[string]interface{}{"id": 102}))
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, int32(103), rows[0][1].(*Person).ID)
+
+	rows, err = q.SelectJoin(nil, "")
+	assert.EqualError(t, err, "reform: no views in SelectJoin")
+	assert.Nil(t, rows)
+}
+
+func TestForEach(t *testing.T) {
+	db, tx := setupTX(t)
+	defer teardown(t, db)
+	defer func() {
+		assert.NoError(t, tx.Rollback())
+	}()
+
+	q := tx.Querier
+
+	expected, err := q.SelectAllFrom(ProjectTable, "ORDER BY id")
+	require.NoError(t, err)
+	require.NotEmpty(t, expected)
+
+	var actual []reform.Struct
+	var last reform.Struct
+	err = q.ForEach(ProjectTable, "ORDER BY id", nil, func(str reform.Struct) error {
+		if last != nil {
+			assert.Same(t, last, str, "struct should be reused")
+		}
+		last = str
+		p := *str.(*Project)
+		actual = append(actual, &p)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+
+	stop := errors.New("stop")
+	var n int
+	err = q.ForEach(ProjectTable, "ORDER BY id", nil, func(str reform.Struct) error {
+		n++
+		return stop
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 1, n)
+
+	err = q.ForEach(ProjectTable, "WHERE invalid_tail", nil, func(str reform.Struct) error {
+		panic("not reached")
+	})
+	assert.Error(t, err)
+
+	iter, err := q.Iterate(ProjectTable, "WHERE id = "+q.Placeholder(1)+" OR id = "+q.Placeholder(2)+" ORDER BY id", "baron", "queen")
+	require.NoError(t, err)
+	iter.AllocatePerRow = true
+	actual = nil
+	for iter.Next() {
+		actual = append(actual, iter.Struct())
+	}
+	require.NoError(t, iter.Err())
+	require.Len(t, actual, 2)
+	assert.Equal(t, "baron", actual[0].(*Project).ID)
+	assert.Equal(t, "queen", actual[1].(*Project).ID)
+	assert.False(t, iter.Next())
+	assert.Nil(t, iter.Struct())
+	assert.NoError(t, iter.Close())
+
+	// close early, then query again in the same transaction
+	iter, err = q.Iterate(ProjectTable, "ORDER BY id")
+	require.NoError(t, err)
+	require.True(t, iter.Next())
+	assert.NoError(t, iter.Close())
+	assert.False(t, iter.Next())
+	_, err = q.Count(ProjectTable, "")
+	assert.NoError(t, err)
+}
+
+func TestSelectPage(t *testing.T) {
+	db, tx := setupTX(t)
+	defer teardown(t, db)
+	defer func() {
+		assert.NoError(t, tx.Rollback())
+	}()
+
+	// ExpandedComparison is used for dialects without RowComparisonDialect
+	expanded := *tx.Querier
+	expanded.Dialect = minimalDialect{tx.Dialect}
+
+	for name, q := range map[string]*reform.Querier{"default": tx.Querier, "expanded": &expanded} {
+		q := q
+		t.Run(name, func(t *testing.T) {
+			for _, tc := range []struct {
+				view    reform.View
+				columns []string
+				size    int
+				tail    string
+			}{
+				{PersonTable, nil, 2, "ORDER BY id"},
+				{PersonProjectView, []string{"project_id", "person_id"}, 4, "ORDER BY project_id, person_id"},
+				{PersonProjectView, []string{"person_id", "project_id"}, 1, "ORDER BY person_id, project_id"},
+			} {
+				expected, err := q.SelectAllFrom(tc.view, tc.tail)
+				require.NoError(t, err)
+
+				var actual []reform.Struct
+				var cursor string
+				for {
+					page, next, err := q.SelectPage(tc.view, tc.columns, tc.size, cursor)
+					require.NoError(t, err)
+					assert.LessOrEqual(t, len(page), tc.size)
+					actual = append(actual, page...)
+					if next == "" {
+						break
+					}
+					cursor = next
+				}
+				assert.Equal(t, expected, actual)
+			}
+		})
+	}
+
+	q := tx.Querier
+	_, cursor, err := q.SelectPage(PersonTable, nil, 1, "")
+	require.NoError(t, err)
+	require.NotEmpty(t, cursor)
+
+	_, _, err = q.SelectPage(PersonTable, []string{"name"}, 1, cursor)
+	assert.EqualError(t, err, "reform: invalid cursor: columns [id], expected [name]")
+	_, _, err = q.SelectPage(PersonTable, nil, 1, "bogus")
+	assert.Error(t, err)
+	_, _, err = q.SelectPage(PersonTable, []string{"bogus"}, 1, "")
+	assert.EqualError(t, err, "reform: people has no column bogus")
+	_, _, err = q.SelectPage(PersonProjectView, nil, 1, "")
+	assert.EqualError(t, err, "reform: person_project is not a table, columns for pagination should be specified")
+	_, _, err = q.SelectPage(PersonTable, nil, 0, "")
+	assert.EqualError(t, err, "reform: invalid page size 0")
+}
+
+func TestAggregates(t *testing.T) {
+	db, tx := setupTX(t)
+	defer teardown(t, db)
+	defer func() {
+		assert.NoError(t, tx.Rollback())
+	}()
+
+	q := tx.Querier
+	baron := "WHERE project_id = " + q.Placeholder(1)
+
+	exists, err := q.Exists(PersonProjectView, baron, "baron")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = q.Exists(PersonProjectView, baron, "no such project")
+	require.NoError(t, err)
+	assert.False(t, exists)
+	_, err = q.Exists(PersonProjectView, "WHERE invalid_tail")
+	assert.Error(t, err)
+
+	var sum, min, max int64
+	require.NoError(t, q.Sum(PersonProjectView, "person_id", &sum, ""))
+	assert.Equal(t, int64(614), sum)
+	require.NoError(t, q.Min(PersonProjectView, "person_id", &min, baron, "baron"))
+	assert.Equal(t, int64(101), min)
+	require.NoError(t, q.Max(PersonProjectView, "person_id", &max, baron, "baron"))
+	assert.Equal(t, int64(103), max)
+
+	var avg float64
+	require.NoError(t, q.Avg(PersonProjectView, "person_id", &avg, baron, "baron"))
+	assert.Equal(t, float64(102), avg)
+
+	var maxID string
+	require.NoError(t, q.Max(PersonProjectView, "project_id", &maxID, ""))
+	assert.Equal(t, "traveler", maxID)
+
+	var empty *int64
+	require.NoError(t, q.Sum(PersonProjectView, "person_id", &empty, baron, "no such project"))
+	assert.Nil(t, empty)
+
+	count, err := q.CountDistinct(PersonProjectView, "person_id", "")
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+	count, err = q.CountDistinct(PersonProjectView, "project_id", "WHERE person_id = "+q.Placeholder(1), 103)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+}
+
+func TestNamed(t *testing.T) {
+	db, tx := setupTX(t)
+	defer teardown(t, db)
+	defer func() {
+		assert.NoError(t, tx.Rollback())
+	}()
+
+	q := tx.Querier
+
+	// the same parameter is used twice, quoted strings are left as is
+	tail := "WHERE (name = :name OR email = :name) AND name <> ':email' AND id >= @id ORDER BY id"
+	structs, err := q.SelectAllFrom(PersonTable, tail, reform.Named(map[string]interface{}{"name": "Elfrieda Abbott", "id": 103}))
+	require.NoError(t, err)
+	require.Len(t, structs, 1)
+	assert.Equal(t, int32(103), structs[0].(*Person).ID)
+
+	// pointer to struct with "reform:" tags
+	params := &struct {
+		ID int32 `reform:"id"`
+	}{102}
+	var person Person
+	err = q.SelectOneTo(&person, "WHERE id = :id", reform.Named(params))
+	require.NoError(t, err)
+	assert.Equal(t, "Elfrieda Abbott", person.Name)
+
+	// Struct's column names; unused values are allowed
+	count, err := q.Count(PersonTable, "WHERE name = :name", reform.Named(&person))
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	person.Name = "Alice"
+	ra, err := q.UpdateView(&person, []string{"name"}, "WHERE id = :id", reform.Named(&person))
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), ra)
+	count, err = q.Count(PersonTable, "WHERE name = :name", reform.Named(map[string]interface{}{"name": "Alice"}))
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	// aggregate methods
+	var max int64
+	err = q.Max(PersonProjectView, "person_id", &max, "WHERE project_id = :project", reform.Named(map[string]interface{}{"project": "baron"}))
+	require.NoError(t, err)
+	assert.Equal(t, int64(103), max)
+	count, err = q.CountDistinct(PersonProjectView, "project_id", "WHERE person_id = :id", reform.Named(map[string]interface{}{"id": 103}))
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	ra, err = q.DeleteFrom(PersonProjectView, "WHERE project_id = :project", reform.Named(map[string]interface{}{"project": "queen"}))
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), ra)
+
+	// comments and dialect-specific strings are left as is
+	tail = "WHERE name = :name /* :email */ -- :id\n"
+	switch q.Dialect {
+	case mysql.Dialect:
+		tail += "AND name <> 'it\\'s :email'"
+	case postgresql.Dialect:
+		tail += "AND name <> $tag$ :email $tag$"
+	}
+	count, err = q.Count(PersonTable, tail, reform.Named(map[string]interface{}{"name": "Alice"}))
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	_, err = q.Count(PersonTable, "WHERE name = :name", reform.Named(map[string]interface{}{}))
+	assert.EqualError(t, err, "reform: missing value for named parameter name")
+	_, err = q.Count(PersonTable, "WHERE name = :name", reform.Named(map[string]interface{}{"name": "Alice", "id": 1, "email": nil}))
+	assert.EqualError(t, err, "reform: unused named parameters: [email id]")
+	_, err = q.Count(PersonTable, "WHERE name = :name", reform.Named(map[string]interface{}{"name": "Alice"}), 1)
+	assert.EqualError(t, err, "reform: Named should be the only argument")
+}
+
+func TestIn(t *testing.T) {
+	db, tx := setupTX(t)
+	defer teardown(t, db)
+	defer func() {
+		assert.NoError(t, tx.Rollback())
+	}()
+
+	q := tx.Querier
+
+	ids := func(structs []reform.Struct) []int32 {
+		res := make([]int32, len(structs))
+		for i, str := range structs {
+			res[i] = str.(*Person).ID
+		}
+		return res
+	}
+
+	// subsequent placeholder is renumbered
+	tail := "WHERE id IN (" + q.Placeholder(1) + ") AND name = " + q.Placeholder(2) + " ORDER BY id"
+	structs, err := q.SelectAllFrom(PersonTable, tail, reform.In([]int32{1, 101, 102, 103}), "Elfrieda Abbott")
+	require.NoError(t, err)
+	assert.Equal(t, []int32{102, 103}, ids(structs))
+
+	// placeholders in tag and comments are left as is
+	tail = "WHERE id IN (" + q.Placeholder(1) + ") /* " + q.Placeholder(1) + "? */ AND name = " + q.Placeholder(2) +
+		" -- " + q.Placeholder(2) + "?\nORDER BY id"
+	structs, err = q.WithTag("id = "+q.Placeholder(1)+"?").SelectAllFrom(PersonTable, tail,
+		reform.In([]int32{1, 101, 102, 103}), "Elfrieda Abbott")
+	require.NoError(t, err)
+	assert.Equal(t, []int32{102, 103}, ids(structs))
+
+	// dialect-specific strings
+	switch q.Dialect {
+	case postgresql.Dialect:
+		tail = "WHERE name <> $$it's $1$$ AND name <> $tag$?$$$tag$ AND id IN ($1) ORDER BY id"
+	case mysql.Dialect:
+		tail = "WHERE name <> 'it\\'s ?' AND id IN (?) ORDER BY id"
+	default:
+		tail = "WHERE name <> 'C:\\' AND id IN (" + q.Placeholder(1) + ") ORDER BY id"
+	}
+	structs, err = q.SelectAllFrom(PersonTable, tail, reform.In([]int{2, 1}))
+	require.NoError(t, err)
+	assert.Equal(t, []int32{1, 2}, ids(structs))
+
+	// plain slice is not expanded
+	_, err = q.SelectAllFrom(PersonTable, "WHERE id IN ("+q.Placeholder(1)+")", []int{2, 1})
+	assert.Error(t, err)
+
+	// empty list does not match any rows
+	count, err := q.Count(PersonTable, "WHERE id IN ("+q.Placeholder(1)+")", reform.In([]int{}))
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	// named parameters
+	structs, err = q.SelectAllFrom(PersonTable, "WHERE id IN (:ids) AND name <> :name ORDER BY id",
+		reform.Named(map[string]interface{}{"ids": reform.In([]int{1, 2, 101}), "name": "Denis Mills"}))
+	require.NoError(t, err)
+	assert.Equal(t, []int32{2, 101}, ids(structs))
+
+	// UpdateView's placeholders for SET clause are taken into account
+	person := &Person{Name: "Alice"}
+	tail = fmt.Sprintf("WHERE id IN (%s)", q.Placeholder(2))
+	ra, err := q.UpdateView(person, []string{"name"}, tail, reform.In([]int{101, 102}))
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), ra)
+
+	tail = fmt.Sprintf("WHERE person_id IN (%s) AND project_id = %s", q.Placeholder(1), q.Placeholder(2))
+	ra, err = q.DeleteFrom(PersonProjectView, tail, reform.In([]int{101, 102}), "baron")
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), ra)
+
+	assert.Panics(t, func() { reform.In(1) })
 }
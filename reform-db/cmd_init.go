@@ -79,8 +79,8 @@ func convertName(sqlName string) string {
 	return strings.Join(res, "")
 }
 
-// getPrimaryKeyColumn returns single primary key column for given table, or nil.
-func getPrimaryKeyColumn(db *reform.DB, catalog, schema, tableName string) *keyColumnUsage {
+// getPrimaryKeyColumns returns primary key column names for given table, or nil.
+func getPrimaryKeyColumns(db *reform.DB, catalog, schema, tableName string) []string {
 	using := []string{
 		"table_catalog", "table_schema", "table_name",
 		"constraint_catalog", "constraint_schema", "constraint_name",
@@ -99,29 +99,29 @@ func getPrimaryKeyColumn(db *reform.DB, catalog, schema, tableName string) *keyC
 				key_column_usage.table_schema = %s AND
 				key_column_usage.table_name = %s AND
 				constraint_type = 'PRIMARY KEY'
-			ORDER BY ordinal_position DESC`,
+			ORDER BY ordinal_position`,
 		strings.Join(using, " AND "), db.Placeholder(1), db.Placeholder(2), db.Placeholder(3),
 	)
 
-	// get only the first row (with the maximum ordinal_position)
-	row := db.QueryRow(q, catalog, schema, tableName)
-	var key keyColumnUsage
-	if err := row.Scan(key.Pointers()...); err != nil {
-		if errors.Is(err, reform.ErrNoRows) {
-			return nil
-		}
+	rows, err := db.Query(q, catalog, schema, tableName)
+	if err != nil {
 		logger.Fatalf("%s", err)
 	}
+	defer rows.Close()
 
-	if key.OrdinalPosition > 1 {
-		logger.Printf(
-			"Composite primary keys are not supported (found %d columns), skipping it for table %s.",
-			key.OrdinalPosition, tableName,
-		)
-		return nil
+	var res []string
+	for {
+		var key keyColumnUsage
+		if err = db.NextRow(&key, rows); err != nil {
+			break
+		}
+		res = append(res, key.ColumnName)
+	}
+	if !errors.Is(err, reform.ErrNoRows) {
+		logger.Fatalf("%s", err)
 	}
 
-	return &key
+	return res
 }
 
 // initModelsInformationSchema returns structs from database with information_schema.
@@ -141,7 +141,10 @@ func initModelsInformationSchema(db *reform.DB, tablesTail string, typeFunc type
 		}
 		var comments []string
 
-		key := getPrimaryKeyColumn(db, table.TableCatalog, table.TableSchema, table.TableName)
+		keys := make(map[string]struct{})
+		for _, key := range getPrimaryKeyColumns(db, table.TableCatalog, table.TableSchema, table.TableName) {
+			keys[key] = struct{}{}
+		}
 
 		tail := fmt.Sprintf(
 			`WHERE table_catalog = %s AND table_schema = %s AND table_name = %s ORDER BY ordinal_position`,
@@ -164,8 +167,11 @@ func initModelsInformationSchema(db *reform.DB, tablesTail string, typeFunc type
 				Column: column.Name,
 			})
 
-			if key != nil && key.ColumnName == column.Name {
-				str.PKFieldIndex = i
+			if _, ok := keys[column.Name]; ok {
+				if str.PKFieldIndex < 0 {
+					str.PKFieldIndex = i
+				}
+				str.PKFieldIndexes = append(str.PKFieldIndexes, i)
 			}
 		}
 
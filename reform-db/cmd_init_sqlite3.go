@@ -105,13 +105,11 @@ func initModelsSQLite3(db *reform.DB) (structs []StructData) {
 			if err = db.NextRow(&column, rows); err != nil {
 				break
 			}
-			switch column.PK {
-			case 0: // not PK
-				// nothing
-			case 1:
-				str.PKFieldIndex = len(str.Fields)
-			default: // composite PK
-				str.PKFieldIndex = -1
+			if column.PK > 0 { // 1-based index in (possibly composite) primary key
+				if str.PKFieldIndex < 0 {
+					str.PKFieldIndex = len(str.Fields)
+				}
+				str.PKFieldIndexes = append(str.PKFieldIndexes, len(str.Fields))
 			}
 			typ, pack, comment := goTypeSQLite3(column.Type, !column.NotNull)
 			if pack != "" {
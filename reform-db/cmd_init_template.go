@@ -29,7 +29,7 @@ import (
 //reform:{{ .SQLName }}
 type {{ .Type }} struct {
 	{{- range $i, $f := .Fields }}
-    {{ $f.Name }} {{ $f.Type }} ` + "`" + `reform:"{{ $f.Column }}{{ if eq $i $.PKFieldIndex }},pk{{ end }}"` + "`" + ` {{ index $.FieldComments $i }}
+    {{ $f.Name }} {{ $f.Type }} ` + "`" + `reform:"{{ $f.Column }}{{ range $.PKFieldIndexes }}{{ if eq $i . }},pk{{ end }}{{ end }}"` + "`" + ` {{ index $.FieldComments $i }}
 	{{- end }}
 }
 `))
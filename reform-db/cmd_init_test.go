@@ -13,17 +13,26 @@ import (
 func (s *ReformDBSuite) TestInit() {
 	good, err := parse.File("../internal/test/models/good.go")
 	s.Require().NoError(err)
-	s.Require().Len(good, 7)
+	s.Require().Len(good, 9)
 
 	people := good[0]
 	projects := good[1]
 	personProject := good[2]
 	idOnly := good[3]
 	constraints := good[4]
-	compositePK := good[5]
+	compositePK := good[6] // CompositePkRecord, as reform-db detects composite primary key
+	document := good[7]
 
 	// patch difference we don't handle
 	people.Type = strings.ReplaceAll(people.Type, "Person", "People")
+	compositePK.Type = strings.ReplaceAll(compositePK.Type, "CompositePkRecord", "CompositePk")
+	document.Type = strings.ReplaceAll(document.Type, "Document", "Documents")
+	document.Fields[2].Version = false
+	document.Fields[3].AutoCreate = false
+	document.Fields[4].AutoUpdate = false
+	document.Fields[5].SoftDelete = false
+	personProject.Fields[0].FK = ""
+	personProject.Fields[1].FK = ""
 	projects.Type = strings.ReplaceAll(projects.Type, "Project", "Projects")
 	if s.db.Dialect == sqlite3.Dialect {
 		people.Fields[0].Type = strings.ReplaceAll(people.Fields[0].Type, "int32", "int64")
@@ -32,6 +41,8 @@ func (s *ReformDBSuite) TestInit() {
 		idOnly.Fields[0].Type = strings.ReplaceAll(idOnly.Fields[0].Type, "int32", "int64")
 		constraints.Fields[0].Type = strings.ReplaceAll(constraints.Fields[0].Type, "int32", "int64")
 		compositePK.Fields[0].Type = strings.ReplaceAll(compositePK.Fields[0].Type, "int32", "int64")
+		document.Fields[0].Type = strings.ReplaceAll(document.Fields[0].Type, "int32", "int64")
+		document.Fields[2].Type = strings.ReplaceAll(document.Fields[2].Type, "int32", "int64")
 	}
 
 	dir, err := ioutil.TempDir("", "ReformDBTestInit")
@@ -42,7 +53,7 @@ func (s *ReformDBSuite) TestInit() {
 
 	fis, err := ioutil.ReadDir(dir)
 	s.Require().NoError(err)
-	s.Require().Len(fis, 6)
+	s.Require().Len(fis, 7)
 
 	ff := filepath.Join(dir, "people.go")
 	actual, err := parse.File(ff)
@@ -80,6 +91,12 @@ func (s *ReformDBSuite) TestInit() {
 	s.Require().Len(actual, 1)
 	s.Require().Equal(compositePK, actual[0])
 
+	ff = filepath.Join(dir, "documents.go")
+	actual, err = parse.File(ff)
+	s.Require().NoError(err)
+	s.Require().Len(actual, 1)
+	s.Require().Equal(document, actual[0])
+
 	err = os.RemoveAll(dir)
 	s.Require().NoError(err)
 }
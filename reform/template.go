@@ -51,6 +51,39 @@ func (v *{{ .TableType }}) NewStruct() reform.Struct {
 	return new({{ .Type }})
 }
 
+{{- if .HasAutoTime }}
+
+// AutoCreateColumnIndexes returns a new slice of indexes of columns set on insert.
+func (v *{{ .TableType }}) AutoCreateColumnIndexes() []uint {
+	return []uint{ {{- range $i, $f := .AutoCreateFieldIndexes }}{{ if $i }}, {{ end }}{{ $f }}{{ end -}} }
+}
+
+// AutoUpdateColumnIndexes returns a new slice of indexes of columns set on update.
+func (v *{{ .TableType }}) AutoUpdateColumnIndexes() []uint {
+	return []uint{ {{- range $i, $f := .AutoUpdateFieldIndexes }}{{ if $i }}, {{ end }}{{ $f }}{{ end -}} }
+}
+{{- end }}
+
+{{- if .FKFields }}
+
+// ForeignKeys returns a new slice of foreign keys for that view or table.
+func (v *{{ .TableType }}) ForeignKeys() []reform.ForeignKey {
+	return []reform.ForeignKey{
+	{{- range .FKFields }}
+		{Column: {{ printf "%q" .Column }}, RefTable: {{ printf "%q" .FKTable }}, RefColumn: {{ printf "%q" .FKColumn }}},
+	{{- end }}
+	}
+}
+{{- end }}
+
+{{- if ge .SoftDeleteFieldIndex 0 }}
+
+// SoftDeleteColumnIndex returns an index of soft delete column for that view or table in SQL database.
+func (v *{{ .TableType }}) SoftDeleteColumnIndex() uint {
+	return {{ .SoftDeleteFieldIndex }}
+}
+{{- end }}
+
 {{- if .IsTable }}
 
 // NewRecord makes a new record for that table.
@@ -63,6 +96,19 @@ func (v *{{ .TableType }}) PKColumnIndex() uint {
 	return uint(v.s.PKFieldIndex)
 }
 
+// PKColumnIndexes returns a new slice of indexes of all primary key columns for that table in SQL database.
+func (v *{{ .TableType }}) PKColumnIndexes() []uint {
+	return []uint{ {{- range $i, $pk := .PKFieldIndexes }}{{ if $i }}, {{ end }}{{ $pk }}{{ end -}} }
+}
+
+{{- if ge .VersionFieldIndex 0 }}
+
+// VersionColumnIndex returns an index of version column for that table in SQL database.
+func (v *{{ .TableType }}) VersionColumnIndex() uint {
+	return {{ .VersionFieldIndex }}
+}
+{{- end }}
+
 {{- end }}
 
 // {{ .TableVar }} represents {{ .SQLName }} view or table in SQL database.
@@ -108,6 +154,19 @@ func (s *{{ .Type }}) Table() reform.Table {
 	return {{ .TableVar }}
 }
 
+{{- if .IsCompositePK }}
+
+// PKValue panics as that record has composite primary key. Use PKValues instead.
+func (s *{{ .Type }}) PKValue() interface{} {
+	panic("reform: {{ .Type }} has composite primary key, use PKValues")
+}
+
+// PKPointer panics as that record has composite primary key. Use PKPointers instead.
+func (s *{{ .Type }}) PKPointer() interface{} {
+	panic("reform: {{ .Type }} has composite primary key, use PKPointers")
+}
+{{- else }}
+
 // PKValue returns a value of primary key for that record.
 // Returned interface{} value is never untyped nil.
 func (s *{{ .Type }}) PKValue() interface{} {
@@ -119,11 +178,43 @@ func (s *{{ .Type }}) PKValue() interface{} {
 func (s *{{ .Type }}) PKPointer() interface{} {
 	return &s.{{ .PKField.Name }}
 }
+{{- end }}
+
+// PKValues returns a slice of primary key field values for that record.
+// Returned interface{} values are never untyped nils.
+func (s *{{ .Type }}) PKValues() []interface{} {
+	return []interface{}{ {{- range .PKFields }}
+		s.{{ .Name }}, {{- end }}
+	}
+}
+
+// PKPointers returns a slice of pointers to primary key fields for that record.
+// Returned interface{} values are never untyped nils.
+func (s *{{ .Type }}) PKPointers() []interface{} {
+	return []interface{}{ {{- range .PKFields }}
+		&s.{{ .Name }}, {{- end }}
+	}
+}
 
 // HasPK returns true if record has non-zero primary key set, false otherwise.
 func (s *{{ .Type }}) HasPK() bool {
+{{- if .IsCompositePK }}
+	return {{ range $i, $pk := .PKFieldIndexes }}{{ if $i }} &&
+		{{ end }}s.{{ (index $.Fields $pk).Name }} != {{ $.TableVar }}.z[{{ $pk }}]{{ end }}
+{{- else }}
 	return s.{{ .PKField.Name }} != {{ .TableVar }}.z[{{ .TableVar }}.s.PKFieldIndex]
+{{- end }}
+}
+
+{{- if .IsCompositePK }}
+
+// SetPK panics as that record has composite primary key. Use PKPointers instead.
+//
+// Deprecated: prefer direct field assignment where possible.
+func (s *{{ .Type }}) SetPK(pk interface{}) {
+	panic("reform: {{ .Type }} has composite primary key, use PKPointers")
 }
+{{- else }}
 
 // SetPK sets record primary key, if possible.
 //
@@ -131,15 +222,28 @@ func (s *{{ .Type }}) HasPK() bool {
 func (s *{{ .Type }}) SetPK(pk interface{}) {
 	reform.SetPK(s, pk)
 }
+{{- end }}
 
 {{- end }}
 
 // check interfaces
 var (
 	_ reform.View   = {{ .TableVar }}
+{{- if ge .SoftDeleteFieldIndex 0 }}
+	_ reform.SoftDeleteView = {{ .TableVar }}
+{{- end }}
+{{- if .HasAutoTime }}
+	_ reform.AutoTimeView = {{ .TableVar }}
+{{- end }}
+{{- if .FKFields }}
+	_ reform.RelationsView = {{ .TableVar }}
+{{- end }}
 	_ reform.Struct = (*{{ .Type }})(nil)
 {{- if .IsTable }}
 	_ reform.Table  = {{ .TableVar }}
+{{- if ge .VersionFieldIndex 0 }}
+	_ reform.VersionedTable = {{ .TableVar }}
+{{- end }}
 	_ reform.Record = (*{{ .Type }})(nil)
 {{- end }}
 	_ fmt.Stringer  = (*{{ .Type }})(nil)
@@ -0,0 +1,132 @@
+package reform
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// relationKey returns a comparable value of foreign key or referenced column for Querier.Preload,
+// dereferencing pointers. It returns false for NULL values.
+func relationKey(v interface{}) (interface{}, bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, false
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil, false
+	}
+	return rv.Interface(), true
+}
+
+// Preload loads structs from related view referenced by given relation – structs' foreign key column
+// with "fk=table.column" label in "reform:" tag (see RelationsView), and attaches them to structs.
+// Related structs are queried with a single SELECT ... WHERE column IN (...) query
+// (several queries if there are more distinct values than Dialect's MaxParameters allows),
+// so loading structs with their relations doesn't require a query per struct.
+//
+// All structs should belong to the same view/table and implement RelationSetter.
+// Related view's name should match referenced table. Foreign key field and referenced field should have
+// the same type, or a pointer to it. Structs with NULL foreign key or without related struct
+// (for example, soft-deleted) are left untouched.
+func (q *Querier) Preload(structs []Struct, relation string, related View) error {
+	if len(structs) == 0 {
+		return nil
+	}
+
+	view := structs[0].View()
+	var fk *ForeignKey
+	if rv, ok := view.(RelationsView); ok {
+		for _, f := range rv.ForeignKeys() {
+			if f.Column == relation {
+				f := f
+				fk = &f
+				break
+			}
+		}
+	}
+	if fk == nil {
+		return fmt.Errorf("reform: %s has no foreign key %s", view.Name(), relation)
+	}
+	if related.Name() != fk.RefTable {
+		return fmt.Errorf("reform: foreign key %s.%s references %s, got %s", view.Name(), relation, fk.RefTable, related.Name())
+	}
+
+	column, refColumn := -1, -1
+	for i, c := range view.Columns() {
+		if c == fk.Column {
+			column = i
+		}
+	}
+	for i, c := range related.Columns() {
+		if c == fk.RefColumn {
+			refColumn = i
+		}
+	}
+	if column < 0 || refColumn < 0 {
+		// generated ForeignKeys returned unexpected column names
+		panic(fmt.Sprintf("reform: unexpected foreign key %s.%s -> %s.%s. Please report this bug.",
+			view.Name(), fk.Column, fk.RefTable, fk.RefColumn))
+	}
+
+	// collect distinct values of foreign key
+	setters := make([]RelationSetter, len(structs))
+	seen := make(map[interface{}]struct{}, len(structs))
+	var args []interface{}
+	for i, str := range structs {
+		if str.View() != view {
+			return fmt.Errorf("reform: different tables in Preload: %s and %s", view.Name(), str.View().Name())
+		}
+		s, ok := str.(RelationSetter)
+		if !ok {
+			return fmt.Errorf("reform: %T doesn't implement RelationSetter", str)
+		}
+		setters[i] = s
+
+		key, ok := relationKey(str.Values()[column])
+		if !ok {
+			continue
+		}
+		if _, ok = seen[key]; !ok {
+			seen[key] = struct{}{}
+			args = append(args, key)
+		}
+	}
+
+	// query related structs in chunks
+	size := len(args)
+	if max := maxParameters(q.Dialect); max > 0 && size > max {
+		size = max
+	}
+	found := make(map[interface{}]Struct, len(args))
+	for len(args) > 0 {
+		chunk := args
+		if len(chunk) > size {
+			chunk = chunk[:size]
+		}
+		args = args[len(chunk):]
+
+		res, err := q.FindAllFrom(related, fk.RefColumn, chunk...)
+		if err != nil {
+			return err
+		}
+		for _, r := range res {
+			if key, ok := relationKey(r.Values()[refColumn]); ok {
+				found[key] = r
+			}
+		}
+	}
+
+	for i, str := range structs {
+		key, ok := relationKey(str.Values()[column])
+		if !ok {
+			continue
+		}
+		if r := found[key]; r != nil {
+			setters[i].SetRelation(relation, r)
+		}
+	}
+	return nil
+}
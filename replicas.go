@@ -0,0 +1,105 @@
+package reform
+
+import (
+	"context"
+	"database/sql"
+	"sync/atomic"
+)
+
+// ReplicaPolicy defines how DB created by NewDBWithReplicas chooses a read replica for a query.
+type ReplicaPolicy int
+
+const (
+	// RoundRobin chooses replicas in turn.
+	RoundRobin ReplicaPolicy = iota
+
+	// LeastLoaded chooses a replica with the least number of connections in use.
+	// It uses Stats() method of *sql.DB; replicas without it are considered idle.
+	LeastLoaded
+)
+
+// replicaSet is a DBTXContext that sends each query to one of read replicas.
+type replicaSet struct {
+	replicas []DBInterface
+	policy   ReplicaPolicy
+	next     uint32
+}
+
+// pick returns replica for the next query.
+func (rs *replicaSet) pick() DBInterface {
+	if len(rs.replicas) == 1 {
+		return rs.replicas[0]
+	}
+
+	if rs.policy == LeastLoaded {
+		res := rs.replicas[0]
+		minInUse := inUse(res)
+		for _, r := range rs.replicas[1:] {
+			if n := inUse(r); n < minInUse {
+				res, minInUse = r, n
+			}
+		}
+		return res
+	}
+
+	n := atomic.AddUint32(&rs.next, 1) - 1
+	return rs.replicas[n%uint32(len(rs.replicas))]
+}
+
+// inUse returns the number of connections in use for *sql.DB or other DBInterface with Stats() method, or zero.
+func inUse(db DBInterface) int {
+	if s, ok := db.(interface{ Stats() sql.DBStats }); ok {
+		return s.Stats().InUse
+	}
+	return 0
+}
+
+func (rs *replicaSet) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return rs.pick().ExecContext(ctx, query, args...)
+}
+
+func (rs *replicaSet) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return rs.pick().QueryContext(ctx, query, args...)
+}
+
+func (rs *replicaSet) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return rs.pick().QueryRowContext(ctx, query, args...)
+}
+
+// NewDBWithReplicas creates new DB object for given primary and read replicas (DBInterfaces).
+// Logger can be nil.
+//
+// Selectors, finders, Count, Exists and aggregate methods of returned DB send queries to replicas
+// chosen by policy. All other methods (including Exec, Query and QueryRow) and all transactions use primary.
+// Use WithPrimary to read your own writes. If there are no replicas, primary is used for everything.
+func NewDBWithReplicas(primary DBInterface, replicas []DBInterface, policy ReplicaPolicy, dialect Dialect, logger Logger) *DB {
+	db := NewDBFromInterface(primary, dialect, logger)
+	if len(replicas) > 0 {
+		db.replicas = &replicaSet{
+			replicas: append([]DBInterface(nil), replicas...),
+			policy:   policy,
+		}
+	}
+	return db
+}
+
+// WithPrimary returns a copy of Querier that sends all queries to primary, even for DB created
+// by NewDBWithReplicas. It can be used to read data written just before, as replicas may lag behind.
+// Returned Querier is tied to the same DB or TX.
+func (q *Querier) WithPrimary() *Querier {
+	newQ := q.clone()
+	newQ.replicas = nil
+	return newQ
+}
+
+// reader returns DBTXContext for read-only queries built by selectors, finders, Count, Exists and aggregate methods:
+// read replicas if they are set, primary otherwise.
+func (q *Querier) reader() DBTXContext {
+	if q.replicas != nil {
+		return q.replicas
+	}
+	return q.dbtxCtx
+}
+
+// check interface
+var _ DBTXContext = (*replicaSet)(nil)
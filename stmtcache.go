@@ -0,0 +1,279 @@
+package reform
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+)
+
+// StmtCache is a cache of prepared statements keyed by query text with LRU eviction.
+// It is safe for concurrent use. See Querier's StmtCache field.
+//
+// Statements are bound to the DB they were prepared on, so a single cache must not be shared
+// between DBs (or between Queriers using different DBInterfaces): use one cache per DB.
+type StmtCache struct {
+	size int
+
+	rw sync.Mutex
+	ll *list.List               // most recently used statements first
+	m  map[string]*list.Element // values are *cachedStmt
+}
+
+// cachedStmt is an element of StmtCache.
+type cachedStmt struct {
+	query   string
+	stmt    *sql.Stmt
+	refs    int  // number of users; statement is closed only when it is not used
+	evicted bool // statement is removed from cache and should be closed when it is not used
+}
+
+// NewStmtCache creates a new statement cache holding up to size prepared statements.
+// Least recently used statements are closed when cache is full.
+func NewStmtCache(size int) *StmtCache {
+	if size <= 0 {
+		panic("reform: StmtCache size should be positive")
+	}
+	return &StmtCache{
+		size: size,
+		ll:   list.New(),
+		m:    make(map[string]*list.Element, size),
+	}
+}
+
+// Len returns the number of cached statements.
+func (c *StmtCache) Len() int {
+	c.rw.Lock()
+	defer c.rw.Unlock()
+
+	return c.ll.Len()
+}
+
+// Close removes all statements from cache and closes them (statements used by active transactions
+// are closed when those transactions end). Cache can be used after that.
+// It returns the first error, if any.
+func (c *StmtCache) Close() error {
+	c.rw.Lock()
+	defer c.rw.Unlock()
+
+	var err error
+	for c.ll.Len() > 0 {
+		if e := c.evict(c.ll.Front()); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// get returns cached statement for query, or prepares and caches a new one with p.
+// Caller should call release when statement is no longer used.
+func (c *StmtCache) get(ctx context.Context, p preparer, query string) (*cachedStmt, error) {
+	c.rw.Lock()
+	if e, ok := c.m[query]; ok {
+		c.ll.MoveToFront(e)
+		cs := e.Value.(*cachedStmt)
+		cs.refs++
+		c.rw.Unlock()
+		return cs, nil
+	}
+	c.rw.Unlock()
+
+	// prepare without holding the lock
+	stmt, err := p.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	c.rw.Lock()
+	defer c.rw.Unlock()
+
+	// other goroutine may have prepared the same statement
+	if e, ok := c.m[query]; ok {
+		_ = stmt.Close()
+		c.ll.MoveToFront(e)
+		cs := e.Value.(*cachedStmt)
+		cs.refs++
+		return cs, nil
+	}
+
+	cs := &cachedStmt{query: query, stmt: stmt, refs: 1}
+	c.m[query] = c.ll.PushFront(cs)
+	for c.ll.Len() > c.size {
+		_ = c.evict(c.ll.Back())
+	}
+	return cs, nil
+}
+
+// acquire returns cached statement for query without preparing a new one, or nil.
+// Caller should call release when statement is no longer used.
+func (c *StmtCache) acquire(query string) *cachedStmt {
+	c.rw.Lock()
+	defer c.rw.Unlock()
+
+	e, ok := c.m[query]
+	if !ok {
+		return nil
+	}
+	c.ll.MoveToFront(e)
+	cs := e.Value.(*cachedStmt)
+	cs.refs++
+	return cs
+}
+
+// release marks statement returned by get as no longer used by caller.
+func (c *StmtCache) release(cs *cachedStmt) {
+	c.rw.Lock()
+	defer c.rw.Unlock()
+
+	cs.refs--
+	if cs.evicted && cs.refs == 0 {
+		_ = cs.stmt.Close()
+	}
+}
+
+// remove removes statement for query from cache, if any.
+func (c *StmtCache) remove(query string) {
+	c.rw.Lock()
+	defer c.rw.Unlock()
+
+	if e, ok := c.m[query]; ok {
+		_ = c.evict(e)
+	}
+}
+
+// evict removes list element from cache and closes its statement if it is not used.
+// Caller should hold the lock.
+func (c *StmtCache) evict(e *list.Element) error {
+	cs := c.ll.Remove(e).(*cachedStmt)
+	delete(c.m, cs.query)
+	cs.evicted = true
+	if cs.refs == 0 {
+		return cs.stmt.Close()
+	}
+	return nil
+}
+
+// preparer is implemented by *sql.DB and *sql.Tx.
+type preparer interface {
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// txStmter is implemented by *sql.Tx.
+type txStmter interface {
+	preparer
+	StmtContext(ctx context.Context, stmt *sql.Stmt) *sql.Stmt
+}
+
+// txStmts holds transaction-specific statements derived from StmtCache's statements.
+// Queries that are not cached yet are sent as is: preparing them on DB's connection pool may need
+// another connection, and statements prepared on transaction can't be cached, as they are closed when it ends.
+// Derived statements are closed by database/sql when transaction ends;
+// cached statements they are derived from are released by TX's Commit and Rollback.
+type txStmts struct {
+	tx      txStmter
+	rw      sync.Mutex
+	m       map[string]*sql.Stmt
+	parents []*cachedStmt
+}
+
+// releaseStmts releases cached statements used by transaction.
+func (q *Querier) releaseStmts() {
+	ts := q.txStmts
+	if ts == nil {
+		return
+	}
+
+	ts.rw.Lock()
+	defer ts.rw.Unlock()
+
+	for _, cs := range ts.parents {
+		q.StmtCache.release(cs)
+	}
+	ts.parents = nil
+	ts.m = make(map[string]*sql.Stmt)
+}
+
+// stmtDBTX executes a prepared statement, ignoring query text.
+type stmtDBTX struct {
+	stmt *sql.Stmt
+}
+
+func (s stmtDBTX) ExecContext(ctx context.Context, _ string, args ...interface{}) (sql.Result, error) {
+	return s.stmt.ExecContext(ctx, args...)
+}
+
+func (s stmtDBTX) QueryContext(ctx context.Context, _ string, args ...interface{}) (*sql.Rows, error) {
+	return s.stmt.QueryContext(ctx, args...)
+}
+
+func (s stmtDBTX) QueryRowContext(ctx context.Context, _ string, args ...interface{}) *sql.Row {
+	return s.stmt.QueryRowContext(ctx, args...)
+}
+
+// target returns DBTXContext that should execute query: prepared statement from StmtCache,
+// or given dbtxCtx if cache is not used or statement can't be prepared.
+// Queries sent to read replicas are not cached; transactions only use statements that are already cached.
+// Caller should call returned function after query is executed.
+func (q *Querier) target(dbtxCtx DBTXContext, query string) (DBTXContext, func()) {
+	noop := func() {}
+	if q.StmtCache == nil {
+		return dbtxCtx, noop
+	}
+	if _, ok := dbtxCtx.(*replicaSet); ok {
+		return dbtxCtx, noop
+	}
+
+	if q.txStmts == nil {
+		// statements prepared on transaction can't be cached by StmtCache, as they are closed when it ends
+		if _, ok := dbtxCtx.(txStmter); ok {
+			return dbtxCtx, noop
+		}
+		p, ok := dbtxCtx.(preparer)
+		if !ok {
+			return dbtxCtx, noop
+		}
+		cs, err := q.StmtCache.get(q.ctx, p, query)
+		if err != nil {
+			return dbtxCtx, noop
+		}
+		return stmtDBTX{cs.stmt}, func() { q.StmtCache.release(cs) }
+	}
+
+	ts := q.txStmts
+	ts.rw.Lock()
+	defer ts.rw.Unlock()
+
+	if stmt, ok := ts.m[query]; ok {
+		return stmtDBTX{stmt}, noop
+	}
+
+	cs := q.StmtCache.acquire(query)
+	if cs == nil {
+		return dbtxCtx, noop
+	}
+
+	// cached statement is released at the end of transaction, as derived statement may use it
+	ts.parents = append(ts.parents, cs)
+	stmt := ts.tx.StmtContext(q.ctx, cs.stmt)
+	ts.m[query] = stmt
+	return stmtDBTX{stmt}, noop
+}
+
+// invalidate removes cached statement for query if err is a connection error.
+func (q *Querier) invalidate(query string, err error) {
+	if q.StmtCache == nil || err == nil {
+		return
+	}
+	if !errors.Is(err, driver.ErrBadConn) && !errors.Is(err, sql.ErrConnDone) {
+		return
+	}
+
+	q.StmtCache.remove(query)
+	if ts := q.txStmts; ts != nil {
+		ts.rw.Lock()
+		delete(ts.m, query)
+		ts.rw.Unlock()
+	}
+}
@@ -0,0 +1,242 @@
+package reform
+
+import (
+	"fmt"
+	"strings"
+)
+
+// tailCondition represents a single condition or a group of conditions in WHERE clause.
+type tailCondition struct {
+	or     bool          // true for OR, false for AND; ignored for the first condition
+	column string        // column name; empty for group
+	op     string        // operator, e.g. "=", "<>", ">", "IN", "LIKE"
+	args   []interface{} // condition arguments
+	group  *TailBuilder  // group of conditions, or nil
+}
+
+// TailBuilder builds query tails with arguments for Querier's methods like SelectAllFrom, SelectRows,
+// UpdateView, DeleteFrom, and Count. It uses Dialect's Placeholder and QuoteIdentifier methods,
+// so the same code works for all supported databases:
+//
+//  tail, args := reform.NewTailBuilder(q).
+//      Where("name", "=", "Alice").
+//      Or("email", "LIKE", "%@example.com").
+//      OrderBy("id").
+//      Limit(10).
+//      Build()
+//  people, err := q.SelectAllFrom(PersonTable, tail, args...)
+//
+// Methods modify and return the same TailBuilder for chaining.
+//
+// OrderBy, Limit and Offset should be used only for SELECT queries.
+type TailBuilder struct {
+	dialect    Dialect
+	conditions []tailCondition
+	orderBy    []string
+	limit      int
+	offset     int
+}
+
+// NewTailBuilder creates a new TailBuilder for given Dialect.
+// As Querier (and DB and TX) implements Dialect, it can be passed there too.
+func NewTailBuilder(dialect Dialect) *TailBuilder {
+	return &TailBuilder{
+		dialect: dialect,
+		limit:   -1,
+	}
+}
+
+func (tb *TailBuilder) add(or bool, column, op string, args []interface{}, group *TailBuilder) *TailBuilder {
+	tb.conditions = append(tb.conditions, tailCondition{
+		or:     or,
+		column: column,
+		op:     op,
+		args:   args,
+		group:  group,
+	})
+	return tb
+}
+
+// Where adds condition "column op arg" to WHERE clause, combining it with previous conditions with AND.
+// If arg is nil and op is "=" or "<>", it adds "column IS NULL" or "column IS NOT NULL" condition.
+func (tb *TailBuilder) Where(column, op string, arg interface{}) *TailBuilder {
+	return tb.add(false, column, op, []interface{}{arg}, nil)
+}
+
+// And is an alias for Where.
+func (tb *TailBuilder) And(column, op string, arg interface{}) *TailBuilder {
+	return tb.Where(column, op, arg)
+}
+
+// Or adds condition "column op arg" to WHERE clause, combining it with previous conditions with OR.
+// Please note that AND has a higher precedence than OR in SQL; use AndGroup and OrGroup to control that.
+func (tb *TailBuilder) Or(column, op string, arg interface{}) *TailBuilder {
+	return tb.add(true, column, op, []interface{}{arg}, nil)
+}
+
+// In adds condition "column IN (args...)" to WHERE clause, combining it with previous conditions with AND.
+// If there are no args, condition is always false.
+func (tb *TailBuilder) In(column string, args ...interface{}) *TailBuilder {
+	return tb.add(false, column, "IN", args, nil)
+}
+
+// AndGroup adds conditions of group in parentheses to WHERE clause,
+// combining it with previous conditions with AND.
+// Group's Dialect, OrderBy, Limit and Offset are ignored.
+func (tb *TailBuilder) AndGroup(group *TailBuilder) *TailBuilder {
+	return tb.add(false, "", "", nil, group)
+}
+
+// OrGroup adds conditions of group in parentheses to WHERE clause,
+// combining it with previous conditions with OR.
+// Group's Dialect, OrderBy, Limit and Offset are ignored.
+func (tb *TailBuilder) OrGroup(group *TailBuilder) *TailBuilder {
+	return tb.add(true, "", "", nil, group)
+}
+
+// OrderBy adds given columns to ORDER BY clause in ascending order.
+func (tb *TailBuilder) OrderBy(columns ...string) *TailBuilder {
+	for _, c := range columns {
+		tb.orderBy = append(tb.orderBy, tb.dialect.QuoteIdentifier(c))
+	}
+	return tb
+}
+
+// OrderByDesc adds given columns to ORDER BY clause in descending order.
+func (tb *TailBuilder) OrderByDesc(columns ...string) *TailBuilder {
+	for _, c := range columns {
+		tb.orderBy = append(tb.orderBy, tb.dialect.QuoteIdentifier(c)+" DESC")
+	}
+	return tb
+}
+
+// Limit sets a maximum number of returned rows.
+func (tb *TailBuilder) Limit(limit int) *TailBuilder {
+	tb.limit = limit
+	return tb
+}
+
+// Offset sets a number of skipped rows.
+func (tb *TailBuilder) Offset(offset int) *TailBuilder {
+	tb.offset = offset
+	return tb
+}
+
+// renderConditions returns rendered conditions and arguments with placeholders starting from given index.
+func (tb *TailBuilder) renderConditions(dialect Dialect, start int) (string, []interface{}) {
+	var res strings.Builder
+	var args []interface{}
+	for i, c := range tb.conditions {
+		if i > 0 {
+			if c.or {
+				res.WriteString(" OR ")
+			} else {
+				res.WriteString(" AND ")
+			}
+		}
+
+		if c.group != nil {
+			s, a := c.group.renderConditions(dialect, start+len(args))
+			res.WriteString("(" + s + ")")
+			args = append(args, a...)
+			continue
+		}
+
+		column := dialect.QuoteIdentifier(c.column)
+
+		if c.op == "IN" {
+			if len(c.args) == 0 {
+				res.WriteString("1 = 0")
+				continue
+			}
+			placeholders := make([]string, len(c.args))
+			for j := range c.args {
+				placeholders[j] = dialect.Placeholder(start + len(args) + j)
+			}
+			res.WriteString(fmt.Sprintf("%s IN (%s)", column, strings.Join(placeholders, ", ")))
+			args = append(args, c.args...)
+			continue
+		}
+
+		arg := c.args[0]
+		if arg == nil {
+			switch c.op {
+			case "=":
+				res.WriteString(column + " IS NULL")
+				continue
+			case "<>", "!=":
+				res.WriteString(column + " IS NOT NULL")
+				continue
+			}
+		}
+
+		res.WriteString(fmt.Sprintf("%s %s %s", column, c.op, dialect.Placeholder(start+len(args))))
+		args = append(args, arg)
+	}
+
+	return res.String(), args
+}
+
+// Build returns a query tail and arguments with placeholders starting from 1.
+func (tb *TailBuilder) Build() (string, []interface{}) {
+	return tb.BuildFrom(1)
+}
+
+// BuildFrom returns a query tail and arguments with placeholders starting from given index.
+// It is useful for UpdateView, where first placeholders are used by SET clause:
+//
+//  tail, args := tb.BuildFrom(len(columns) + 1)
+//  _, err := q.UpdateView(str, columns, tail, args...)
+func (tb *TailBuilder) BuildFrom(start int) (string, []interface{}) {
+	var parts []string
+	var args []interface{}
+
+	if len(tb.conditions) > 0 {
+		var where string
+		where, args = tb.renderConditions(tb.dialect, start)
+		parts = append(parts, "WHERE "+where)
+	}
+
+	orderBy := tb.orderBy
+	limited := tb.limit >= 0 || tb.offset > 0
+
+	switch tb.dialect.SelectLimitMethod() {
+	case Limit:
+		if len(orderBy) > 0 {
+			parts = append(parts, "ORDER BY "+strings.Join(orderBy, ", "))
+		}
+		if limited {
+			limit := tb.limit
+			if limit < 0 {
+				// there is no portable way to set offset without limit
+				parts = append(parts, "LIMIT 9223372036854775807")
+			} else {
+				parts = append(parts, fmt.Sprintf("LIMIT %d", limit))
+			}
+			if tb.offset > 0 {
+				parts = append(parts, fmt.Sprintf("OFFSET %d", tb.offset))
+			}
+		}
+
+	case SelectTop:
+		// "SELECT TOP N" can't be expressed in tail, so use "OFFSET M ROWS FETCH NEXT N ROWS ONLY",
+		// which requires ORDER BY clause
+		if len(orderBy) == 0 && limited {
+			orderBy = []string{"(SELECT NULL)"}
+		}
+		if len(orderBy) > 0 {
+			parts = append(parts, "ORDER BY "+strings.Join(orderBy, ", "))
+		}
+		if limited {
+			parts = append(parts, fmt.Sprintf("OFFSET %d ROWS", tb.offset))
+			if tb.limit >= 0 {
+				parts = append(parts, fmt.Sprintf("FETCH NEXT %d ROWS ONLY", tb.limit))
+			}
+		}
+
+	default:
+		panic("reform: Unhandled SelectLimitMethod. Please report this bug.")
+	}
+
+	return strings.Join(parts, " "), args
+}
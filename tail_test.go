@@ -0,0 +1,107 @@
+package reform_test
+
+import (
+	"testing"
+
+	"github.com/AlekSi/pointer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gopkg.in/reform.v1"
+	"gopkg.in/reform.v1/dialects/mysql"
+	"gopkg.in/reform.v1/dialects/postgresql"
+	"gopkg.in/reform.v1/dialects/sqlserver"
+	. "gopkg.in/reform.v1/internal/test/models"
+)
+
+func TestTailBuilderBuild(t *testing.T) {
+	t.Parallel()
+
+	build := func(dialect reform.Dialect) *reform.TailBuilder {
+		return reform.NewTailBuilder(dialect).
+			Where("name", "=", "Alice").
+			OrGroup(reform.NewTailBuilder(dialect).Where("email", "<>", nil).In("id", 1, 2)).
+			OrderBy("name").
+			OrderByDesc("id").
+			Limit(10).
+			Offset(20)
+	}
+
+	for dialect, expected := range map[reform.Dialect]string{
+		postgresql.Dialect: `WHERE "name" = $2 OR ("email" IS NOT NULL AND "id" IN ($3, $4)) ` +
+			`ORDER BY "name", "id" DESC LIMIT 10 OFFSET 20`,
+		mysql.Dialect: "WHERE `name` = ? OR (`email` IS NOT NULL AND `id` IN (?, ?)) " +
+			"ORDER BY `name`, `id` DESC LIMIT 10 OFFSET 20",
+		sqlserver.Dialect: `WHERE [name] = @P2 OR ([email] IS NOT NULL AND [id] IN (@P3, @P4)) ` +
+			`ORDER BY [name], [id] DESC OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY`,
+	} {
+		dialect, expected := dialect, expected
+		t.Run(dialect.String(), func(t *testing.T) {
+			t.Parallel()
+
+			tail, args := build(dialect).BuildFrom(2)
+			assert.Equal(t, expected, tail)
+			assert.Equal(t, []interface{}{"Alice", 1, 2}, args)
+		})
+	}
+
+	t.Run("Empty", func(t *testing.T) {
+		t.Parallel()
+
+		tail, args := reform.NewTailBuilder(postgresql.Dialect).Build()
+		assert.Equal(t, "", tail)
+		assert.Nil(t, args)
+	})
+
+	t.Run("EmptyIn", func(t *testing.T) {
+		t.Parallel()
+
+		tail, args := reform.NewTailBuilder(postgresql.Dialect).In("id").Where("name", "=", nil).Build()
+		assert.Equal(t, `WHERE 1 = 0 AND "name" IS NULL`, tail)
+		assert.Nil(t, args)
+	})
+
+	t.Run("OffsetOnly", func(t *testing.T) {
+		t.Parallel()
+
+		tail, _ := reform.NewTailBuilder(mysql.Dialect).Offset(5).Build()
+		assert.Equal(t, "LIMIT 9223372036854775807 OFFSET 5", tail)
+
+		tail, _ = reform.NewTailBuilder(sqlserver.Dialect).Limit(5).Build()
+		assert.Equal(t, "ORDER BY (SELECT NULL) OFFSET 0 ROWS FETCH NEXT 5 ROWS ONLY", tail)
+	})
+}
+
+func TestTailBuilderQueries(t *testing.T) {
+	db, tx := setupTX(t)
+	defer teardown(t, db)
+	defer func() {
+		assert.NoError(t, tx.Rollback())
+	}()
+
+	q := tx.Querier
+
+	tail, args := reform.NewTailBuilder(q).Where("name", "=", "Elfrieda Abbott").OrderByDesc("id").Limit(1).Offset(1).Build()
+	structs, err := q.SelectAllFrom(PersonTable, tail, args...)
+	require.NoError(t, err)
+	assert.Equal(t, []reform.Struct{
+		&Person{ID: 102, GroupID: pointer.ToInt32(65534), Name: "Elfrieda Abbott", Email: pointer.ToString("elfrieda_abbott@example.org"), CreatedAt: personCreated},
+	}, structs)
+
+	tail, args = reform.NewTailBuilder(q).In("id", 1, 2, 102).AndGroup(reform.NewTailBuilder(q).Where("email", "=", nil).Or("id", ">", 100)).Build()
+	count, err := q.Count(PersonTable, tail, args...)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	person := &Person{Name: "Updated"}
+	columns := []string{"name"}
+	tail, args = reform.NewTailBuilder(q).In("id", 1, 2).BuildFrom(len(columns) + 1)
+	updated, err := q.UpdateView(person, columns, tail, args...)
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), updated)
+
+	tail, args = reform.NewTailBuilder(q).Where("name", "=", "Updated").Build()
+	deleted, err := q.DeleteFrom(PersonTable, tail, args...)
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), deleted)
+}
@@ -37,5 +37,14 @@ CREATE TABLE composite_pk (
   PRIMARY KEY ([i], [j])
 );
 
+CREATE TABLE documents (
+  [id] int identity(1, 1) PRIMARY KEY,
+  [title] varchar(255) NOT NULL,
+  [version] int NOT NULL DEFAULT 0,
+  [created_at] datetime2 NOT NULL,
+  [updated_at] datetime2,
+  [deleted_at] datetime2
+);
+
 -- to allow insert test data with IDs
 SET IDENTITY_INSERT people ON;
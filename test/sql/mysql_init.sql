@@ -50,3 +50,13 @@ CREATE TABLE composite_pk (
   j varchar(255) NOT NULL,
   PRIMARY KEY (i, j)
 );
+
+CREATE TABLE documents (
+  id int NOT NULL AUTO_INCREMENT,
+  title varchar(255) NOT NULL,
+  version int NOT NULL DEFAULT 0,
+  created_at datetime NOT NULL,
+  updated_at datetime,
+  deleted_at datetime,
+  PRIMARY KEY (id)
+);
@@ -39,9 +39,24 @@ CREATE TABLE composite_pk (
   PRIMARY KEY (i, j)
 );
 
+CREATE TABLE documents (
+  id serial PRIMARY KEY,
+  title varchar NOT NULL,
+  version integer NOT NULL DEFAULT 0,
+  created_at timestamp with time zone NOT NULL,
+  updated_at timestamp with time zone,
+  deleted_at timestamp with time zone
+);
+
 CREATE SCHEMA legacy;
 
 CREATE TABLE legacy.people (
   id serial PRIMARY KEY,
   name varchar
 );
+
+CREATE TABLE legacy.documents (
+  id serial PRIMARY KEY,
+  title varchar NOT NULL,
+  deleted_at timestamp with time zone
+);
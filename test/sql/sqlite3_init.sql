@@ -36,3 +36,12 @@ CREATE TABLE composite_pk (
   j varchar NOT NULL,
   PRIMARY KEY (i, j)
 );
+
+CREATE TABLE documents (
+  id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  title varchar NOT NULL,
+  version integer NOT NULL DEFAULT 0,
+  created_at datetime NOT NULL,
+  updated_at datetime,
+  deleted_at datetime
+);
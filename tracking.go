@@ -0,0 +1,162 @@
+package reform
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Snapshotter is an optional interface for Struct which enables dirty tracking for Querier.UpdateChanged.
+// Querier stores a snapshot of struct's values after it is loaded by selectors and finders,
+// inserted, or updated. It is implemented by Tracker.
+type Snapshotter interface {
+	// Snapshot returns stored snapshot of struct's values, or nil.
+	Snapshot() []interface{}
+
+	// SetSnapshot stores snapshot of struct's values.
+	SetSnapshot(values []interface{})
+}
+
+// Tracker implements Snapshotter. Embed it into struct without "reform:" tag to enable dirty tracking:
+//
+//	//reform:people
+//	type Person struct {
+//	    reform.Tracker
+//	    ID   int32  `reform:"id,pk"`
+//	    Name string `reform:"name"`
+//	}
+type Tracker struct {
+	snapshot []interface{}
+}
+
+// Snapshot implements Snapshotter.
+func (t *Tracker) Snapshot() []interface{} {
+	return t.snapshot
+}
+
+// SetSnapshot implements Snapshotter.
+func (t *Tracker) SetSnapshot(values []interface{}) {
+	t.snapshot = values
+}
+
+// snapshotValue returns a copy of value that is not affected by future changes of struct's field:
+// pointers and byte slices are copied.
+func snapshotValue(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	switch {
+	case rv.Kind() == reflect.Ptr && !rv.IsNil():
+		p := reflect.New(rv.Type().Elem())
+		p.Elem().Set(rv.Elem())
+		return p.Interface()
+	case rv.Kind() == reflect.Slice && !rv.IsNil():
+		s := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+		reflect.Copy(s, rv)
+		return s.Interface()
+	default:
+		return v
+	}
+}
+
+// snapshot stores a snapshot of struct's values for given columns (all if nil) if struct implements Snapshotter.
+// If there is no stored snapshot yet, it is stored only for all columns.
+func (q *Querier) snapshot(str Struct, columns []string) {
+	s, ok := str.(Snapshotter)
+	if !ok {
+		return
+	}
+
+	values := str.Values()
+	if columns == nil {
+		snapshot := make([]interface{}, len(values))
+		for i, v := range values {
+			snapshot[i] = snapshotValue(v)
+		}
+		s.SetSnapshot(snapshot)
+		return
+	}
+
+	snapshot := s.Snapshot()
+	if snapshot == nil {
+		return
+	}
+	for _, c := range columns {
+		for i, vc := range str.View().Columns() {
+			if c == vc {
+				snapshot[i] = snapshotValue(values[i])
+			}
+		}
+	}
+}
+
+// changedColumns returns names of columns with values different from snapshot.
+func changedColumns(str Struct, snapshot []interface{}) []string {
+	values := str.Values()
+	if len(values) != len(snapshot) {
+		panic(fmt.Sprintf("reform: snapshot has %d values, struct has %d. Please report this bug.", len(snapshot), len(values)))
+	}
+
+	var res []string
+	columns := str.View().Columns()
+	for i, v := range values {
+		if !snapshotEqual(v, snapshot[i]) {
+			res = append(res, columns[i])
+		}
+	}
+	return res
+}
+
+// snapshotEqual returns true if field value v is equal to snapshot value s.
+// Times are compared with Equal, so different locations and monotonic clock readings are ignored.
+func snapshotEqual(v, s interface{}) bool {
+	switch v := v.(type) {
+	case time.Time:
+		s, ok := s.(time.Time)
+		return ok && v.Equal(s)
+	case *time.Time:
+		s, ok := s.(*time.Time)
+		if !ok || v == nil || s == nil {
+			return ok && v == nil && s == nil
+		}
+		return v.Equal(*s)
+	default:
+		return reflect.DeepEqual(snapshotValue(v), s)
+	}
+}
+
+// UpdateChanged updates columns of row specified by primary key in SQL database table with given record,
+// but only those that were changed since record was loaded, inserted, or updated.
+// It requires record to implement Snapshotter (for example, by embedding Tracker);
+// otherwise, or if there is no snapshot, it works like Update.
+// If there are no changed columns, it does nothing, and hooks are not called.
+// Otherwise it works like UpdateColumns for changed columns (and columns changed by BeforeUpdater).
+//
+// Method returns ErrNoRows if no rows were updated.
+// Method returns ErrNoPK if primary key is not set.
+// Method returns ErrStaleRecord if row's version doesn't match record's version.
+func (q *Querier) UpdateChanged(record Record) error {
+	s, ok := record.(Snapshotter)
+	if !ok || s.Snapshot() == nil {
+		return q.Update(record)
+	}
+
+	snapshot := s.Snapshot()
+	if len(changedColumns(record, snapshot)) == 0 {
+		return nil
+	}
+
+	autoColumns, err := q.beforeUpdate(record)
+	if err != nil {
+		return err
+	}
+	if !record.HasPK() {
+		return ErrNoPK
+	}
+
+	columns := withColumns(changedColumns(record, snapshot), autoColumns)
+	columns, values, err := filteredColumnsAndValues(record, columns, true)
+	if err != nil {
+		return err
+	}
+
+	return q.updateRecord(record, columns, values, false)
+}
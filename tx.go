@@ -3,6 +3,7 @@ package reform
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"time"
 )
 
@@ -26,7 +27,8 @@ var _ TXInterface = (*sql.Tx)(nil)
 // TX represents a SQL database transaction.
 type TX struct {
 	*Querier
-	tx TXInterface
+	tx         TXInterface
+	savepoints int // number of savepoints created by InTransaction
 }
 
 // NewTX creates new TX object for given SQL database transaction.
@@ -54,6 +56,7 @@ func (tx *TX) Commit() error {
 	tx.logBefore("COMMIT", nil)
 	start := time.Now()
 	err := tx.tx.Commit()
+	tx.releaseStmts()
 	tx.logAfter("COMMIT", nil, time.Since(start), err)
 	return err
 }
@@ -63,10 +66,73 @@ func (tx *TX) Rollback() error {
 	tx.logBefore("ROLLBACK", nil)
 	start := time.Now()
 	err := tx.tx.Rollback()
+	tx.releaseStmts()
 	tx.logAfter("ROLLBACK", nil, time.Since(start), err)
 	return err
 }
 
+// Savepoint creates a savepoint with given name in the transaction.
+func (tx *TX) Savepoint(name string) error {
+	query := "SAVEPOINT " + tx.QuoteIdentifier(name)
+	if savepointMethod(tx.Dialect) == SaveTransaction {
+		query = "SAVE TRANSACTION " + tx.QuoteIdentifier(name)
+	}
+	_, err := tx.Exec(query)
+	return err
+}
+
+// RollbackTo rolls back the transaction to the savepoint with given name.
+// All changes made after the savepoint was created are discarded, but the transaction remains active.
+func (tx *TX) RollbackTo(name string) error {
+	query := "ROLLBACK TO SAVEPOINT " + tx.QuoteIdentifier(name)
+	if savepointMethod(tx.Dialect) == SaveTransaction {
+		query = "ROLLBACK TRANSACTION " + tx.QuoteIdentifier(name)
+	}
+	_, err := tx.Exec(query)
+	return err
+}
+
+// Release releases the savepoint with given name, keeping all changes made after it was created.
+// It does nothing for dialects with SaveTransaction method, as their savepoints can't be released.
+func (tx *TX) Release(name string) error {
+	if savepointMethod(tx.Dialect) == SaveTransaction {
+		return nil
+	}
+	_, err := tx.Exec("RELEASE SAVEPOINT " + tx.QuoteIdentifier(name))
+	return err
+}
+
+// InTransaction wraps function execution in a savepoint (a nested transaction),
+// rolling back to it in case of error or panic, releasing it otherwise.
+// That allows to compose code written against DB.InTransaction inside an outer transaction.
+func (tx *TX) InTransaction(f func(t *TX) error) error {
+	tx.savepoints++
+	defer func() { tx.savepoints-- }()
+	name := fmt.Sprintf("reform_%d", tx.savepoints)
+
+	if err := tx.Savepoint(name); err != nil {
+		return err
+	}
+
+	var released bool
+	defer func() {
+		if !released {
+			// always return f() or Release() error, not possible RollbackTo() or Release() error
+			_ = tx.RollbackTo(name)
+			_ = tx.Release(name)
+		}
+	}()
+
+	err := f(tx)
+	if err == nil {
+		err = tx.Release(name)
+	}
+	if err == nil {
+		released = true
+	}
+	return err
+}
+
 // check interfaces
 var (
 	_ DBTX        = (*TX)(nil)
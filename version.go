@@ -0,0 +1,52 @@
+package reform
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// versionUpdate holds the state of optimistic locking for a single record update.
+type versionUpdate struct {
+	column string        // version column name
+	old    interface{}   // current version value
+	next   interface{}   // incremented version value
+	field  reflect.Value // record's version field
+}
+
+// newVersionUpdate returns versionUpdate for given record and index of version column.
+func newVersionUpdate(record Record, index uint) *versionUpdate {
+	field := reflect.ValueOf(record.Pointers()[index]).Elem()
+	next := reflect.New(field.Type()).Elem()
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		next.SetInt(field.Int() + 1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		next.SetUint(field.Uint() + 1)
+	default:
+		panic(fmt.Sprintf("reform: unexpected version field type %s. Please report this bug.", field.Type()))
+	}
+
+	return &versionUpdate{
+		column: record.Table().Columns()[index],
+		old:    field.Interface(),
+		next:   next.Interface(),
+		field:  field,
+	}
+}
+
+// set returns columns and values with incremented version column value,
+// replacing existing version value or adding it.
+func (vu *versionUpdate) set(columns []string, values []interface{}) ([]string, []interface{}) {
+	for i, c := range columns {
+		if c == vu.column {
+			values[i] = vu.next
+			return columns, values
+		}
+	}
+	return append(columns, vu.column), append(values, vu.next)
+}
+
+// commit sets record's version field to incremented value after successful update.
+func (vu *versionUpdate) commit() {
+	vu.field.Set(reflect.ValueOf(vu.next))
+}